@@ -0,0 +1,23 @@
+package ai
+
+import (
+	"CodeQuizAI/config"
+)
+
+func init() {
+	Register("deepseek", newDeepseek)
+}
+
+// newDeepseek DeepSeek（OpenAI兼容的 chat completions 接口）
+// 文档地址：https://platform.deepseek.com/api-docs/
+func newDeepseek(cfg config.AIModelConfig) (Provider, error) {
+	if cfg.APIKey == "" {
+		return nil, ErrNotConfigured
+	}
+
+	return newChatCompletions(cfg, chatCompletionsDefaults{
+		BaseURL:  "https://api.deepseek.com/v1",
+		Model:    "deepseek-chat",
+		JSONMode: true,
+	}), nil
+}
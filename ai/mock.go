@@ -0,0 +1,428 @@
+package ai
+
+import (
+	"CodeQuizAI/config"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register("mock", newMock)
+}
+
+// 模拟的故障类型
+const (
+	mockFailureNone      = ""          // 正常返回
+	mockFailureMalformed = "malformed" // 返回非法JSON
+	mockFailureTimeout   = "timeout"   // 阻塞直到请求超时或取消
+	mockFailureError     = "error"     // 返回厂商错误（503）
+	mockFailureInvalid   = "invalid"   // 偶数题（第2、4...题）的答案不合格（选择题超出选项范围，其他题型为空）
+)
+
+// mock 离线模拟模型：从提示语中解析题目数量、语言、题型等参数，同一提示语返回相同的题目，不访问网络
+// 通过 MOCK_ENABLED=true 启用；MOCK_FAILURE 配置全局故障类型，
+// 也可通过请求参数 mock_failure（malformed/timeout/error/invalid，见 WithMockFailure）针对单次请求模拟故障，
+// 加上批次序号（如 "error@2"）则只在分批生成的该批次模拟故障；
+// 按资料出题时题目以资料内容为主题并标注出处行号
+type mock struct {
+	failure    string        // 全局故障类型
+	latency    time.Duration // 模拟的响应延迟
+	chunkDelay time.Duration // 流式输出时每段内容之间的间隔
+}
+
+// mockChunkSize 流式输出时每段内容的最大字符数
+const mockChunkSize = 32
+
+func newMock(cfg config.AIModelConfig) (Provider, error) {
+	enabled, _ := strconv.ParseBool(cfg.Get("ENABLED", "false"))
+	if !enabled {
+		return nil, ErrNotConfigured
+	}
+
+	p := &mock{failure: cfg.Get("FAILURE", mockFailureNone)}
+	switch p.failure {
+	case mockFailureNone, mockFailureMalformed, mockFailureTimeout, mockFailureError, mockFailureInvalid:
+	default:
+		return nil, fmt.Errorf("MOCK_FAILURE 必须是 malformed/timeout/error/invalid 或为空，当前值: %s", p.failure)
+	}
+	if ms, err := strconv.Atoi(cfg.Get("LATENCY_MS", "0")); err == nil && ms > 0 {
+		p.latency = time.Duration(ms) * time.Millisecond
+	}
+	if ms, err := strconv.Atoi(cfg.Get("CHUNK_DELAY_MS", "0")); err == nil && ms > 0 {
+		p.chunkDelay = time.Duration(ms) * time.Millisecond
+	}
+	return p, nil
+}
+
+func (p *mock) Name() string {
+	return "mock"
+}
+
+func (p *mock) Chat(ctx context.Context, req *Request) (*Response, error) {
+	// 1. 模拟响应延迟
+	if p.latency > 0 {
+		select {
+		case <-time.After(p.latency):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// 2. 模拟故障
+	task := parseMockTask(req.Prompt)
+	failure := p.failureFor(ctx, task)
+	switch failure {
+	case mockFailureMalformed:
+		return &Response{Content: `[{"title":"这是一个被截断的响应","options":["A. `}, nil
+	case mockFailureTimeout:
+		<-ctx.Done()
+		return nil, ctx.Err()
+	case mockFailureError:
+		return nil, &APIError{
+			Provider:   p.Name(),
+			StatusCode: http.StatusServiceUnavailable,
+			Code:       "mock_unavailable",
+			Message:    "模拟的厂商错误",
+		}
+	}
+
+	// 3. 校验请求：独立作答
+	if task.review {
+		content, err := json.Marshal(mockReview(task))
+		if err != nil {
+			return nil, err
+		}
+		return &Response{
+			Content: string(content),
+			Usage:   Usage{PromptTokens: mockTokens(req.Prompt), CompletionTokens: mockTokens(string(content))},
+		}, nil
+	}
+
+	// 4. 生成确定性的题目
+	questions := mockQuestions(task)
+	if failure == mockFailureInvalid {
+		for i := 1; i < len(questions); i += 2 {
+			questions[i].Answer = ""
+			if len(questions[i].Options) > 0 {
+				questions[i].Answer = "E"
+			}
+		}
+	}
+	content, err := json.Marshal(questions)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{
+		Content: string(content),
+		Usage:   Usage{PromptTokens: mockTokens(req.Prompt), CompletionTokens: mockTokens(string(content))},
+	}, nil
+}
+
+// mockTokens 粗略估算文本的 token 数（约每2个字符1个token），用于模拟用量统计
+func mockTokens(text string) int {
+	return (len([]rune(text)) + 1) / 2
+}
+
+// ChatStream 将完整内容按固定长度切分后逐段输出（故障模拟与 Chat 一致）
+func (p *mock) ChatStream(ctx context.Context, req *Request, onDelta func(delta string)) (*Response, error) {
+	resp, err := p.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	runes := []rune(resp.Content)
+	for start := 0; start < len(runes); start += mockChunkSize {
+		if start > 0 && p.chunkDelay > 0 {
+			select {
+			case <-time.After(p.chunkDelay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		end := min(start+mockChunkSize, len(runes))
+		onDelta(string(runes[start:end]))
+	}
+	return resp, nil
+}
+
+// mockFailureKey 上下文中单次请求模拟故障的键
+type mockFailureKey struct{}
+
+// WithMockFailure 为本次调用附加模拟的故障（如 "error" 或 "error@2"），只有 mock 模型读取，不改变提示语
+func WithMockFailure(ctx context.Context, failure string) context.Context {
+	return context.WithValue(ctx, mockFailureKey{}, failure)
+}
+
+// failureFor 计算本次请求的故障类型（上下文中的单次请求故障优先于全局配置）。
+// 带批次序号时（如 "error@2"）只对分批生成的该批次生效
+func (p *mock) failureFor(ctx context.Context, task mockTask) string {
+	requested, _ := ctx.Value(mockFailureKey{}).(string)
+	if requested == "" {
+		return p.failure
+	}
+	failure, part, ok := strings.Cut(requested, "@")
+	if ok {
+		if n, err := strconv.Atoi(part); err != nil || n != task.part {
+			return p.failure
+		}
+	}
+	return failure
+}
+
+// 提示语中的题型名称与题型的对应关系
+var mockQuestionTypes = map[string]string{
+	"单选题":   "single",
+	"多选题":   "multiple",
+	"判断题":   "true_false",
+	"填空题":   "fill_blank",
+	"简答题":   "short_answer",
+	"代码输出题": "code_output",
+}
+
+var (
+	// mockTaskPattern 匹配提示语中的题目数量、编程语言和题型（如 "请生成5道关于Go语言的单选题"、"以下2道关于Go语言的单选题未通过校验"）
+	mockTaskPattern = regexp.MustCompile(`(\d+)道关于(.+?)语言的(单选题|多选题|判断题|填空题|简答题|代码输出题)`)
+	// mockKeywordsPattern 匹配提示语中的知识点（如 "围绕“goroutine、channel”这些知识点"）
+	mockKeywordsPattern = regexp.MustCompile(`围绕“(.+?)”`)
+	// mockPartPattern 匹配分批生成的批次序号（如 "分批生成的第2/3批"）
+	mockPartPattern = regexp.MustCompile(`分批生成的第(\d+)/\d+批`)
+	// mockSourceLinePattern 匹配带行号的资料内容中的一行（如 "12| func main() {"）
+	mockSourceLinePattern = regexp.MustCompile(`(?m)^(\d+)\| (.*)$`)
+	// mockReviewItemPattern 匹配校验提示语中的一道待作答题目（如 `第1题：{"title":"..."}`）
+	mockReviewItemPattern = regexp.MustCompile(`(?m)^第\d+题：(\{.*\})$`)
+)
+
+// mockReviewMarker 校验模型独立作答的提示语开头
+const mockReviewMarker = "请独立作答"
+
+// mockTask 从提示语中解析出的生成参数（提示语未写明时使用默认值：1道关于Go语言的单选题）
+type mockTask struct {
+	count        int              // 题目数量
+	language     string           // 编程语言
+	questionType string           // 题型
+	topic        string           // 题目主题（资料的第一行非空内容、知识点或编程语言）
+	part         int              // 分批生成时的批次序号（未分批时为0）
+	sourceLines  []int            // 资料中非空行的行号（按资料出题时）
+	review       bool             // 是否为校验模型独立作答的请求
+	reviewItems  []mockReviewItem // 待作答的题目
+	seed         int64            // 随机种子（由完整提示语计算，同一提示语返回相同的结果）
+}
+
+// mockReviewItem 校验提示语中的待作答题目
+type mockReviewItem struct {
+	Title       string   `json:"title"`
+	Options     []string `json:"options"`
+	CodeSnippet string   `json:"code_snippet"`
+}
+
+// parseMockTask 从提示语中解析生成参数
+func parseMockTask(prompt string) mockTask {
+	h := fnv.New64a()
+	h.Write([]byte(prompt))
+	task := mockTask{count: 1, language: "Go", questionType: "single", seed: int64(h.Sum64())}
+
+	if m := mockTaskPattern.FindStringSubmatch(prompt); m != nil {
+		task.count, _ = strconv.Atoi(m[1])
+		task.language = m[2]
+		task.questionType = mockQuestionTypes[m[3]]
+	}
+	task.topic = task.language
+	if m := mockKeywordsPattern.FindStringSubmatch(prompt); m != nil {
+		task.topic = m[1]
+	}
+	if m := mockPartPattern.FindStringSubmatch(prompt); m != nil {
+		task.part, _ = strconv.Atoi(m[1])
+	}
+	for _, m := range mockSourceLinePattern.FindAllStringSubmatch(prompt, -1) {
+		if line := strings.TrimSpace(m[2]); line != "" {
+			if len(task.sourceLines) == 0 {
+				task.topic = "「" + string([]rune(line)[:min(len([]rune(line)), 30)]) + "」"
+			}
+			n, _ := strconv.Atoi(m[1])
+			task.sourceLines = append(task.sourceLines, n)
+		}
+	}
+	if strings.HasPrefix(prompt, mockReviewMarker) {
+		task.review = true
+		for _, m := range mockReviewItemPattern.FindAllStringSubmatch(prompt, -1) {
+			var item mockReviewItem
+			if json.Unmarshal([]byte(m[1]), &item) == nil {
+				task.reviewItems = append(task.reviewItems, item)
+			}
+		}
+	}
+	return task
+}
+
+// mockQuestion 与题目生成提示语约定的输出格式一致
+// Answer 的格式因题型而异：选择题为字母字符串，判断题为布尔值，填空题为字符串数组，简答题为对象，代码输出题为程序输出
+type mockQuestion struct {
+	Title        string      `json:"title"`
+	CodeSnippet  string      `json:"code_snippet,omitempty"`
+	CodeLanguage string      `json:"code_language,omitempty"`
+	Options      []string    `json:"options,omitempty"`
+	Answer       interface{} `json:"answer"`
+	Explanation  string      `json:"explanation,omitempty"`
+	SourceLines  string      `json:"source_lines,omitempty"`
+}
+
+// mockQuestions 以提示语为种子生成题目，按资料出题时随机标注资料中的一行作为出处
+func mockQuestions(task mockTask) []mockQuestion {
+	rng := rand.New(rand.NewSource(task.seed))
+	topic := task.topic
+
+	questions := make([]mockQuestion, 0, task.count)
+	for i := 0; i < task.count; i++ {
+		serial := fmt.Sprintf("%s中%s的第%d题（#%04d）", task.language, topic, i+1, rng.Intn(10000))
+		switch task.questionType {
+		case "true_false":
+			answer := rng.Intn(2) == 0
+			questions = append(questions, mockQuestion{
+				Title:       fmt.Sprintf("[mock] 关于%s：%s 相关说法 %d 是否正确？", serial, topic, rng.Intn(1000)),
+				Answer:      answer,
+				Explanation: fmt.Sprintf("离线模拟数据，正确答案为 %t。", answer),
+			})
+		case "fill_blank":
+			word := fmt.Sprintf("keyword%d", rng.Intn(1000))
+			questions = append(questions, mockQuestion{
+				Title:       fmt.Sprintf("[mock] 关于%s：%s 中用于该用途的关键字是 ____。", serial, topic),
+				Answer:      []string{word, "re:^(?i)" + word + "$"},
+				Explanation: fmt.Sprintf("离线模拟数据，正确答案为 %s。", word),
+			})
+		case "short_answer":
+			questions = append(questions, mockQuestion{
+				Title: fmt.Sprintf("[mock] 关于%s：简述%s的作用。", serial, topic),
+				Answer: map[string]interface{}{
+					"reference": fmt.Sprintf("%s 的参考答案 %d。", topic, rng.Intn(1000)),
+					"rubric":    []string{"说明基本概念", "给出使用场景", "指出注意事项"},
+				},
+				Explanation: "离线模拟数据。",
+			})
+		case "code_output":
+			questions = append(questions, mockCodeQuestion(rng, task, serial))
+		default:
+			questions = append(questions, mockChoiceQuestion(rng, task, topic, serial, i))
+		}
+		if len(task.sourceLines) > 0 {
+			line := task.sourceLines[rng.Intn(len(task.sourceLines))]
+			questions[i].SourceLines = fmt.Sprintf("%d-%d", line, line)
+		}
+	}
+	return questions
+}
+
+// mockChoiceQuestion 生成选择题：单选题一个正确答案，多选题两个正确答案
+func mockChoiceQuestion(rng *rand.Rand, task mockTask, topic, serial string, index int) mockQuestion {
+	labels := []string{"A", "B", "C", "D"}
+	options := make([]string, len(labels))
+	for j, label := range labels {
+		options[j] = fmt.Sprintf("%s. %s 相关说法 %d-%d", label, topic, index+1, rng.Intn(1000))
+	}
+
+	perm := rng.Perm(len(labels))
+	answerCount := 1
+	if task.questionType == "multiple" {
+		answerCount = 2
+	}
+	picked := make([]string, 0, answerCount)
+	for _, idx := range perm[:answerCount] {
+		picked = append(picked, labels[idx])
+	}
+	answer := sortedLetters(picked)
+
+	return mockQuestion{
+		Title:       fmt.Sprintf("[mock] 关于%s，以下哪些说法正确？", serial),
+		Options:     options,
+		Answer:      answer,
+		Explanation: fmt.Sprintf("离线模拟数据，正确答案为 %s。", answer),
+	}
+}
+
+// mockCodeQuestion 生成代码输出题：打印两个随机数之和的程序（Go、Python、JavaScript 之外的语言使用 Python 写法）
+func mockCodeQuestion(rng *rand.Rand, task mockTask, serial string) mockQuestion {
+	a, b := rng.Intn(100), rng.Intn(100)
+	var code, language string
+	switch strings.ToLower(task.language) {
+	case "go":
+		code = fmt.Sprintf("package main\n\nimport \"fmt\"\n\nfunc main() {\n\ta, b := %d, %d\n\tfmt.Println(a + b)\n}", a, b)
+		language = "go"
+	case "javascript":
+		code = fmt.Sprintf("const a = %d, b = %d;\nconsole.log(a + b);", a, b)
+		language = "javascript"
+	default:
+		code = fmt.Sprintf("a, b = %d, %d\nprint(a + b)", a, b)
+		language = "python"
+	}
+	return mockQuestion{
+		Title:        fmt.Sprintf("[mock] 关于%s：以下程序的输出是什么？", serial),
+		CodeSnippet:  code,
+		CodeLanguage: language,
+		Answer:       fmt.Sprint(a + b),
+		Explanation:  fmt.Sprintf("离线模拟数据，%d + %d = %d。", a, b, a+b),
+	}
+}
+
+// mockReviewAnswer 与答案校验提示语约定的输出格式一致
+type mockReviewAnswer struct {
+	Index     int         `json:"index"`
+	Answer    interface{} `json:"answer"`
+	Reasoning string      `json:"reasoning"`
+}
+
+// mockSumPattern 匹配模拟代码输出题中的两个加数（如 "a, b := 12, 34"）
+var mockSumPattern = regexp.MustCompile(`(\d+), (\d+)`)
+
+// mockReview 模拟独立作答：代码输出题按程序计算两数之和（与生成的答案一致），
+// 其他题型以题目标题为种子随机作答（可能与生成的答案不一致）
+func mockReview(task mockTask) []mockReviewAnswer {
+	answers := make([]mockReviewAnswer, 0, len(task.reviewItems))
+	for i, item := range task.reviewItems {
+		h := fnv.New64a()
+		h.Write([]byte(item.Title))
+		rng := rand.New(rand.NewSource(int64(h.Sum64())))
+
+		answer := mockReviewAnswer{Index: i + 1, Reasoning: "离线模拟作答。"}
+		switch task.questionType {
+		case "true_false":
+			answer.Answer = rng.Intn(2) == 0
+		case "fill_blank":
+			answer.Answer = fmt.Sprintf("keyword%d", rng.Intn(1000))
+		case "code_output":
+			if m := mockSumPattern.FindStringSubmatch(item.CodeSnippet); m != nil {
+				a, _ := strconv.Atoi(m[1])
+				b, _ := strconv.Atoi(m[2])
+				answer.Answer = fmt.Sprint(a + b)
+				answer.Reasoning = fmt.Sprintf("离线模拟作答：程序输出 %d + %d 的结果。", a, b)
+			}
+		default:
+			count := 1
+			if task.questionType == "multiple" {
+				count = 2
+			}
+			labels := make([]string, 0, count)
+			for _, idx := range rng.Perm(max(len(item.Options), count))[:count] {
+				labels = append(labels, string(rune('A'+idx)))
+			}
+			answer.Answer = sortedLetters(labels)
+		}
+		answers = append(answers, answer)
+	}
+	return answers
+}
+
+// sortedLetters 将答案字母按字母序拼接（如 ["C","A"] -> "AC"）
+func sortedLetters(letters []string) string {
+	out := make([]string, len(letters))
+	copy(out, letters)
+	sort.Strings(out)
+	return strings.Join(out, "")
+}
@@ -0,0 +1,265 @@
+package ai
+
+import (
+	"CodeQuizAI/config"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	Register("openai", newOpenAICompatible)
+}
+
+// chatCompletions OpenAI兼容的 chat completions 接口（DeepSeek、本地推理服务等共用）
+type chatCompletions struct {
+	name        string
+	apiKey      string
+	endpoint    string            // 完整接口地址（<BaseURL>/chat/completions）
+	model       string            // 厂商侧模型名
+	headers     map[string]string // 额外请求头
+	jsonMode    bool              // 是否设置 response_format=json_object
+	temperature float64           // 控制随机性，0-1之间
+}
+
+// chatCompletionsDefaults 各厂商的默认参数（可被配置覆盖）
+type chatCompletionsDefaults struct {
+	BaseURL  string
+	Model    string
+	JSONMode bool
+}
+
+// newChatCompletions 按配置创建 chat completions 模型，未配置的项使用默认值
+func newChatCompletions(cfg config.AIModelConfig, defaults chatCompletionsDefaults) *chatCompletions {
+	p := &chatCompletions{
+		name:        cfg.Name,
+		apiKey:      cfg.APIKey,
+		model:       defaults.Model,
+		headers:     cfg.Headers,
+		jsonMode:    defaults.JSONMode,
+		temperature: 0.7,
+	}
+
+	baseURL := defaults.BaseURL
+	if cfg.BaseURL != "" {
+		baseURL = cfg.BaseURL
+	}
+	p.endpoint = strings.TrimRight(baseURL, "/") + "/chat/completions"
+
+	if cfg.Model != "" {
+		p.model = cfg.Model
+	}
+	if v, err := strconv.ParseBool(cfg.Get("JSON_MODE", "")); err == nil {
+		p.jsonMode = v
+	}
+	if v, err := strconv.ParseFloat(cfg.Get("TEMPERATURE", ""), 64); err == nil {
+		p.temperature = v
+	}
+	return p
+}
+
+// newOpenAICompatible 通用OpenAI兼容模型：BASE_URL 和 MODEL 必填，API_KEY 可选（本地服务通常无需鉴权）
+func newOpenAICompatible(cfg config.AIModelConfig) (Provider, error) {
+	if cfg.BaseURL == "" {
+		return nil, ErrNotConfigured
+	}
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("%s 已配置 BASE_URL 但缺少 MODEL", cfg.Name)
+	}
+	return newChatCompletions(cfg, chatCompletionsDefaults{}), nil
+}
+
+func (p *chatCompletions) Name() string {
+	return p.name
+}
+
+// newHTTPRequest 构造 chat completions 请求（含鉴权和额外请求头）
+func (p *chatCompletions) newHTTPRequest(ctx context.Context, req *Request, stream bool) (*http.Request, error) {
+	body := map[string]interface{}{
+		"model": p.model,
+		"messages": []map[string]string{
+			{
+				"role":    "user",
+				"content": req.Prompt,
+			},
+		},
+		"temperature": p.temperature,
+	}
+	if p.jsonMode {
+		body["response_format"] = map[string]string{
+			"type": "json_object", // 强制返回JSON格式
+		}
+	}
+	if stream {
+		body["stream"] = true
+		body["stream_options"] = map[string]bool{
+			"include_usage": true, // 最后一条消息返回 token 用量
+		}
+	}
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("构造请求体失败: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.endpoint, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	for key, value := range p.headers {
+		httpReq.Header.Set(key, value)
+	}
+	return httpReq, nil
+}
+
+// chatError chat completions 接口的错误结构
+type chatError struct {
+	Message string `json:"message"`
+	Code    string `json:"code"`
+}
+
+// chatUsage chat completions 接口返回的 token 用量
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+func (u *chatUsage) usage() Usage {
+	if u == nil {
+		return Usage{}
+	}
+	return Usage{PromptTokens: u.PromptTokens, CompletionTokens: u.CompletionTokens}
+}
+
+func (p *chatCompletions) Chat(ctx context.Context, req *Request) (*Response, error) {
+	// 1. 构造请求
+	httpReq, err := p.newHTTPRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 发送请求
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("请求发送失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 3. 处理响应
+	var chatResp struct {
+		Choices []struct {
+			Message struct {
+				Role    string `json:"role"`
+				Content string `json:"content"` // 包含生成的题目JSON
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+			Index        int    `json:"index"`
+		} `json:"choices"`
+		Usage *chatUsage `json:"usage"`
+		Error *chatError `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, &APIError{Provider: p.name, StatusCode: resp.StatusCode, Message: resp.Status}
+		}
+		return nil, fmt.Errorf("响应解析失败: %w", err)
+	}
+
+	// 4. 错误映射
+	if chatResp.Error != nil {
+		return nil, &APIError{
+			Provider:   p.name,
+			StatusCode: resp.StatusCode,
+			Code:       chatResp.Error.Code,
+			Message:    chatResp.Error.Message,
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{Provider: p.name, StatusCode: resp.StatusCode, Message: resp.Status}
+	}
+
+	// 5. 提取生成的内容（确保有返回结果）
+	if len(chatResp.Choices) == 0 || chatResp.Choices[0].Message.Content == "" {
+		return nil, errors.New(p.name + "未返回有效内容")
+	}
+
+	return &Response{Content: chatResp.Choices[0].Message.Content, Usage: chatResp.Usage.usage()}, nil
+}
+
+func (p *chatCompletions) ChatStream(ctx context.Context, req *Request, onDelta func(delta string)) (*Response, error) {
+	// 1. 构造请求（stream=true）
+	httpReq, err := p.newHTTPRequest(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 发送请求
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("请求发送失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 3. 非200响应按普通JSON解析错误信息
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error *chatError `json:"error"`
+		}
+		apiErr := &APIError{Provider: p.name, StatusCode: resp.StatusCode, Message: resp.Status}
+		if json.NewDecoder(resp.Body).Decode(&errResp) == nil && errResp.Error != nil {
+			apiErr.Code = errResp.Error.Code
+			apiErr.Message = errResp.Error.Message
+		}
+		return nil, apiErr
+	}
+
+	// 4. 逐条读取增量内容（data: {...}，以 data: [DONE] 结束）
+	var content strings.Builder
+	var usage Usage
+	err = readSSE(resp.Body, func(data string) error {
+		if data == "[DONE]" {
+			return io.EOF
+		}
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Usage *chatUsage `json:"usage"`
+			Error *chatError `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("流式响应解析失败: %w", err)
+		}
+		if chunk.Error != nil {
+			return &APIError{Provider: p.name, StatusCode: resp.StatusCode, Code: chunk.Error.Code, Message: chunk.Error.Message}
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage.usage()
+		}
+		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			content.WriteString(chunk.Choices[0].Delta.Content)
+			onDelta(chunk.Choices[0].Delta.Content)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. 确保有返回结果
+	if content.Len() == 0 {
+		return nil, errors.New(p.name + "未返回有效内容")
+	}
+	return &Response{Content: content.String(), Usage: usage}, nil
+}
@@ -0,0 +1,146 @@
+package ai
+
+import (
+	"CodeQuizAI/config"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Request 一次AI调用的请求参数
+type Request struct {
+	Prompt string // 提示语
+}
+
+// Response 一次AI调用的返回结果
+type Response struct {
+	Content string // 模型生成的文本内容
+	Usage   Usage  // token 用量（厂商未返回时为0）
+}
+
+// Usage 一次AI调用的 token 用量
+type Usage struct {
+	PromptTokens     int // 提示语 token 数
+	CompletionTokens int // 生成内容 token 数
+}
+
+// Provider AI模型提供方，负责请求构造、鉴权、响应解析和错误映射
+type Provider interface {
+	// Name 模型名（与请求中的 ai_model 一致）
+	Name() string
+	// Chat 发送提示语并返回模型生成的内容
+	Chat(ctx context.Context, req *Request) (*Response, error)
+}
+
+// Factory 根据配置创建Provider，配置不完整时返回 ErrNotConfigured
+type Factory func(cfg config.AIModelConfig) (Provider, error)
+
+// ErrNotConfigured 模型未配置（如缺少API Key），启动时跳过该模型
+var ErrNotConfigured = errors.New("AI模型未配置")
+
+// ErrUnsupportedModel 请求了未注册或未启用的模型
+var ErrUnsupportedModel = errors.New("不支持的AI模型")
+
+// APIError 厂商接口返回的错误
+type APIError struct {
+	Provider   string // 模型名
+	StatusCode int    // HTTP状态码（非HTTP错误时为0）
+	Code       string // 厂商错误码
+	Message    string // 厂商错误信息
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("%s API错误: %s (代码: %s, 状态码: %d)", e.Provider, e.Message, e.Code, e.StatusCode)
+	}
+	return fmt.Sprintf("%s API错误: %s (状态码: %d)", e.Provider, e.Message, e.StatusCode)
+}
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)  // 已注册的模型
+	providers = make(map[string]Provider) // 已启用的模型
+)
+
+// Register 注册模型，各模型在自己文件的 init 中调用
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := factories[name]; exists {
+		panic("ai: 重复注册模型 " + name)
+	}
+	factories[name] = factory
+}
+
+// Setup 按配置启用所有已注册的模型，至少需要一个可用模型
+func Setup(cfg *config.Config) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	policy := Policy{
+		Timeout:          time.Duration(cfg.AITimeoutSeconds) * time.Second,
+		MaxRetries:       cfg.AIMaxRetries,
+		RetryBase:        time.Duration(cfg.AIRetryBaseMillis) * time.Millisecond,
+		BreakerThreshold: cfg.AIBreakerThreshold,
+		BreakerCooldown:  time.Duration(cfg.AIBreakerCooldownSeconds) * time.Second,
+	}
+
+	enabled := make(map[string]Provider)
+	for name, factory := range factories {
+		p, err := factory(cfg.AIModel(name))
+		if err != nil {
+			if errors.Is(err, ErrNotConfigured) {
+				continue
+			}
+			return fmt.Errorf("初始化AI模型 %s 失败: %w", name, err)
+		}
+		enabled[name] = newResilient(p, policy)
+	}
+
+	if len(enabled) == 0 {
+		return errors.New("至少需要配置一个可用的AI模型（如 TONGYI_API_KEY 或 DEEPSEEK_API_KEY）")
+	}
+
+	providers = enabled
+	log.Printf("已启用AI模型: %v", sortedNames(enabled))
+	return nil
+}
+
+// Get 获取已启用的模型
+func Get(name string) (Provider, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	p, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s（可用模型: %v）", ErrUnsupportedModel, name, sortedNames(providers))
+	}
+	return p, nil
+}
+
+// Models 返回所有已启用的模型名（按字母排序）
+func Models() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return sortedNames(providers)
+}
+
+// 工具函数：返回按字母排序的模型名
+func sortedNames(m map[string]Provider) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// 共享的HTTP客户端（超时由每次调用的上下文控制，见 resilient）
+var httpClient = &http.Client{}
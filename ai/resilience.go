@@ -0,0 +1,262 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrCircuitOpen 模型处于熔断状态，请求被快速拒绝
+var ErrCircuitOpen = errors.New("AI模型暂时不可用（已熔断）")
+
+// ErrTimeout 单次AI调用超时
+var ErrTimeout = errors.New("AI接口调用超时")
+
+// Policy 超时、重试与熔断策略
+type Policy struct {
+	Timeout          time.Duration // 单次调用超时
+	MaxRetries       int           // 最大重试次数
+	RetryBase        time.Duration // 退避基础间隔
+	BreakerThreshold int           // 连续失败多少次后熔断
+	BreakerCooldown  time.Duration // 熔断持续时间
+}
+
+// 熔断器状态
+const (
+	BreakerClosed   = "closed"    // 正常
+	BreakerOpen     = "open"      // 熔断中，快速失败
+	BreakerHalfOpen = "half_open" // 冷却结束，允许一次试探请求
+)
+
+// BreakerStatus 熔断器状态快照（供管理接口展示）
+type BreakerStatus struct {
+	Model               string     `json:"model"`                // 模型名
+	State               string     `json:"state"`                // 熔断状态
+	ConsecutiveFailures int        `json:"consecutive_failures"` // 连续失败次数
+	TotalSuccesses      int64      `json:"total_successes"`      // 累计成功次数
+	TotalFailures       int64      `json:"total_failures"`       // 累计失败次数
+	LastError           string     `json:"last_error,omitempty"` // 最近一次错误
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`  // 最近一次熔断时间
+	RetryAt             *time.Time `json:"retry_at,omitempty"`   // 允许试探请求的时间
+}
+
+// breaker 单个模型的熔断器
+type breaker struct {
+	mu                  sync.Mutex
+	threshold           int
+	cooldown            time.Duration
+	state               string
+	consecutiveFailures int
+	totalSuccesses      int64
+	totalFailures       int64
+	lastError           string
+	openedAt            time.Time
+	probing             bool // 半开状态下是否已有试探请求在进行
+}
+
+// allow 判断是否放行请求
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// onSuccess 记录成功调用，关闭熔断器
+func (b *breaker) onSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.consecutiveFailures = 0
+	b.probing = false
+	b.totalSuccesses++
+}
+
+// onFailure 记录失败调用，达到阈值或试探失败时熔断
+func (b *breaker) onFailure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFailures++
+	b.totalFailures++
+	b.lastError = err.Error()
+	if b.state == BreakerHalfOpen || b.consecutiveFailures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+	b.probing = false
+}
+
+// release 结束试探但不计入成败（如调用方主动取消）
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// snapshot 返回熔断器状态快照
+func (b *breaker) snapshot(model string) BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BreakerStatus{
+		Model:               model,
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		TotalSuccesses:      b.totalSuccesses,
+		TotalFailures:       b.totalFailures,
+		LastError:           b.lastError,
+	}
+	if !b.openedAt.IsZero() {
+		openedAt := b.openedAt
+		retryAt := b.openedAt.Add(b.cooldown)
+		status.OpenedAt = &openedAt
+		status.RetryAt = &retryAt
+	}
+	return status
+}
+
+// resilient 为Provider增加超时、重试和熔断能力
+type resilient struct {
+	Provider
+	policy  Policy
+	breaker *breaker
+}
+
+func newResilient(p Provider, policy Policy) *resilient {
+	return &resilient{
+		Provider: p,
+		policy:   policy,
+		breaker: &breaker{
+			threshold: policy.BreakerThreshold,
+			cooldown:  policy.BreakerCooldown,
+			state:     BreakerClosed,
+		},
+	}
+}
+
+func (r *resilient) Chat(ctx context.Context, req *Request) (*Response, error) {
+	var lastErr error
+	for attempt := 0; attempt <= r.policy.MaxRetries; attempt++ {
+		// 1. 重试前按指数退避等待
+		if attempt > 0 {
+			delay := backoff(r.policy.RetryBase, attempt)
+			log.Printf("AI模型 %s 第%d次重试（%v后），上次错误: %v", r.Name(), attempt, delay, lastErr)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		// 2. 熔断中则快速失败
+		if !r.breaker.allow() {
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, r.Name())
+		}
+
+		// 3. 发起单次调用
+		resp, err := r.attempt(ctx, req)
+		if err == nil {
+			r.breaker.onSuccess()
+			return resp, nil
+		}
+		lastErr = err
+
+		// 4. 调用方取消的请求不计入熔断，也不再重试；超时计入熔断但不重试（单次已等满 AI_TIMEOUT）
+		if ctx.Err() != nil {
+			r.breaker.release()
+			return nil, err
+		}
+		if errors.Is(err, ErrTimeout) {
+			r.breaker.onFailure(err)
+			return nil, err
+		}
+		if !IsRetryable(err) {
+			r.breaker.release()
+			return nil, err
+		}
+		r.breaker.onFailure(err)
+	}
+	return nil, lastErr
+}
+
+// attempt 带超时的单次调用
+func (r *resilient) attempt(ctx context.Context, req *Request) (*Response, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, r.policy.Timeout)
+	defer cancel()
+
+	resp, err := r.Provider.Chat(attemptCtx, req)
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w（%s, %v）: %v", ErrTimeout, r.Name(), r.policy.Timeout, err)
+	}
+	return resp, err
+}
+
+// IsRetryable 判断错误是否可重试：429、5xx、连接重置。
+// 调用超时（ErrTimeout 或 context.DeadlineExceeded）不重试，避免总耗时成倍超过 AI_TIMEOUT
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	if errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// backoff 计算第n次重试的等待时间：base*2^(n-1)，叠加 [0.5, 1) 倍的随机抖动（间隔非正或溢出时不等待）
+func backoff(base time.Duration, attempt int) time.Duration {
+	delay := base << (attempt - 1)
+	if base <= 0 || delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// BreakerStates 返回所有已启用模型的熔断器状态
+func BreakerStates() []BreakerStatus {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	states := make([]BreakerStatus, 0, len(providers))
+	for _, name := range sortedNames(providers) {
+		if r, ok := providers[name].(*resilient); ok {
+			states = append(states, r.breaker.snapshot(name))
+		}
+	}
+	return states
+}
@@ -0,0 +1,97 @@
+package ai
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// StreamProvider 支持流式输出的模型
+type StreamProvider interface {
+	Provider
+	// ChatStream 发送提示语，每收到一段增量内容就回调 onDelta，结束后返回完整内容
+	ChatStream(ctx context.Context, req *Request, onDelta func(delta string)) (*Response, error)
+}
+
+// ChatStream 流式调用：模型不支持流式输出时退化为一次性返回全部内容。
+// 熔断和超时与 Chat 一致；已经输出过内容的调用不再重试，避免客户端收到重复内容
+func (r *resilient) ChatStream(ctx context.Context, req *Request, onDelta func(delta string)) (*Response, error) {
+	streamer, ok := r.Provider.(StreamProvider)
+	if !ok {
+		resp, err := r.Chat(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		onDelta(resp.Content)
+		return resp, nil
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= r.policy.MaxRetries; attempt++ {
+		// 1. 重试前按指数退避等待
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff(r.policy.RetryBase, attempt)):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		// 2. 熔断中则快速失败
+		if !r.breaker.allow() {
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, r.Name())
+		}
+
+		// 3. 发起单次流式调用（超时控制整个流）
+		emitted := false
+		attemptCtx, cancel := context.WithTimeout(ctx, r.policy.Timeout)
+		resp, err := streamer.ChatStream(attemptCtx, req, func(delta string) {
+			emitted = true
+			onDelta(delta)
+		})
+		if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("%w（%s, %v）: %v", ErrTimeout, r.Name(), r.policy.Timeout, err)
+		}
+		cancel()
+		if err == nil {
+			r.breaker.onSuccess()
+			return resp, nil
+		}
+		lastErr = err
+
+		// 4. 调用方取消或不可重试的错误直接返回；超时和已输出内容的调用记为失败但不重试
+		if ctx.Err() != nil || (!IsRetryable(err) && !errors.Is(err, ErrTimeout)) {
+			r.breaker.release()
+			return nil, err
+		}
+		r.breaker.onFailure(err)
+		if emitted || errors.Is(err, ErrTimeout) {
+			return nil, err
+		}
+	}
+	return nil, lastErr
+}
+
+// readSSE 逐条读取 Server-Sent Events 的 data 字段，回调返回 io.EOF 时提前结束
+func readSSE(body io.Reader, onData func(data string) error) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue // 忽略 event/id/注释等其他字段
+		}
+		if err := onData(strings.TrimSpace(data)); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+	return scanner.Err()
+}
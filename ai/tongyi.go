@@ -0,0 +1,183 @@
+package ai
+
+import (
+	"CodeQuizAI/config"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func init() {
+	Register("tongyi", newTongyi)
+}
+
+// tongyi 通义千问（DashScope 文本生成接口）
+type tongyi struct {
+	apiKey  string
+	url     string
+	model   string
+	headers map[string]string
+}
+
+func newTongyi(cfg config.AIModelConfig) (Provider, error) {
+	if cfg.APIKey == "" {
+		return nil, ErrNotConfigured
+	}
+
+	p := &tongyi{
+		apiKey:  cfg.APIKey,
+		url:     "https://dashscope.aliyuncs.com/api/v1/services/aigc/text-generation/generation",
+		model:   "qwen-turbo",
+		headers: cfg.Headers,
+	}
+	if cfg.BaseURL != "" {
+		p.url = cfg.BaseURL
+	}
+	if cfg.Model != "" {
+		p.model = cfg.Model
+	}
+	return p, nil
+}
+
+func (p *tongyi) Name() string {
+	return "tongyi"
+}
+
+// newHTTPRequest 构造 DashScope 文本生成请求
+func (p *tongyi) newHTTPRequest(ctx context.Context, req *Request, stream bool) (*http.Request, error) {
+	body := map[string]interface{}{
+		"model": p.model,
+		"input": map[string]string{"prompt": req.Prompt},
+	}
+	if stream {
+		body["parameters"] = map[string]interface{}{
+			"incremental_output": true, // 每次只返回增量内容
+		}
+	}
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("构造请求体失败: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	if stream {
+		httpReq.Header.Set("X-DashScope-SSE", "enable")
+	}
+	for key, value := range p.headers {
+		httpReq.Header.Set(key, value)
+	}
+	return httpReq, nil
+}
+
+// tongyiResponse DashScope 响应结构（流式和非流式通用）
+type tongyiResponse struct {
+	Output struct {
+		Text string `json:"text"`
+	} `json:"output"`
+	Usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"` // 流式输出时每条消息返回截至当前的累计用量
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (r *tongyiResponse) usage() Usage {
+	return Usage{PromptTokens: r.Usage.InputTokens, CompletionTokens: r.Usage.OutputTokens}
+}
+
+func (p *tongyi) Chat(ctx context.Context, req *Request) (*Response, error) {
+	// 1. 构造请求
+	httpReq, err := p.newHTTPRequest(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 发送请求
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("请求发送失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 3. 解析通义千问响应
+	var tongyiResp tongyiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tongyiResp); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, &APIError{Provider: p.Name(), StatusCode: resp.StatusCode, Message: resp.Status}
+		}
+		return nil, fmt.Errorf("响应解析失败: %w", err)
+	}
+
+	// 4. 错误映射
+	if tongyiResp.Code != "" || resp.StatusCode != http.StatusOK {
+		return nil, &APIError{
+			Provider:   p.Name(),
+			StatusCode: resp.StatusCode,
+			Code:       tongyiResp.Code,
+			Message:    tongyiResp.Message,
+		}
+	}
+
+	return &Response{Content: tongyiResp.Output.Text, Usage: tongyiResp.usage()}, nil
+}
+
+func (p *tongyi) ChatStream(ctx context.Context, req *Request, onDelta func(delta string)) (*Response, error) {
+	// 1. 构造请求（开启SSE和增量输出）
+	httpReq, err := p.newHTTPRequest(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 发送请求
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("请求发送失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// 3. 非200响应按普通JSON解析错误信息
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{Provider: p.Name(), StatusCode: resp.StatusCode, Message: resp.Status}
+		var errResp tongyiResponse
+		if json.NewDecoder(resp.Body).Decode(&errResp) == nil && errResp.Code != "" {
+			apiErr.Code = errResp.Code
+			apiErr.Message = errResp.Message
+		}
+		return nil, apiErr
+	}
+
+	// 4. 逐条读取增量内容（流中的错误同样以 data 事件返回）
+	var content strings.Builder
+	var usage Usage
+	err = readSSE(resp.Body, func(data string) error {
+		var chunk tongyiResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("流式响应解析失败: %w", err)
+		}
+		if chunk.Code != "" {
+			return &APIError{Provider: p.Name(), StatusCode: resp.StatusCode, Code: chunk.Code, Message: chunk.Message}
+		}
+		if u := chunk.usage(); u != (Usage{}) {
+			usage = u
+		}
+		if chunk.Output.Text != "" {
+			content.WriteString(chunk.Output.Text)
+			onDelta(chunk.Output.Text)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{Content: content.String(), Usage: usage}, nil
+}
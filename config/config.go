@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -11,13 +12,65 @@ import (
 
 // Config 结构体映射所有环境变量配置
 type Config struct {
-	// AI API 配置
-	TongyiAPIKey   string // 通义千问 API 密钥
-	DeepseekAPIKey string // Deepseek API 密钥
-
 	// 支持的编程语言（解析为切片方便使用）
 	SupportedLanguages []string
 
+	// AI 调用容错配置
+	AITimeoutSeconds         int // 单次AI调用超时时间（秒）
+	AIMaxRetries             int // 可重试错误的最大重试次数
+	AIRetryBaseMillis        int // 重试退避的基础间隔（毫秒），按指数增长并叠加随机抖动
+	AIBreakerThreshold       int // 连续失败多少次后熔断
+	AIBreakerCooldownSeconds int // 熔断后多久允许试探请求（秒）
+
+	// AI 模型降级配置
+	AIFallbackModels []string // 默认降级模型列表（请求模型失败后按顺序尝试）
+
+	// AI 调用计费配置
+	AIPrices map[string]AIPrice // 各模型的单价（未配置的模型费用按0计算）
+
+	// AI 响应缓存配置
+	AICacheTTLSeconds int // 缓存有效期（秒，0 表示不启用缓存）
+	AICacheMaxEntries int // 最多缓存的响应数量（超出时淘汰最久未使用的）
+
+	// 题目校验配置
+	AIMaxReprompts int // 题目校验不通过时，要求模型修正的最大次数
+
+	// 答案校验配置
+	AIVerifyModel string // 默认的校验模型（独立作答以校验生成的答案，为空时请求需自行指定）
+
+	// 近似重复检测配置
+	DuplicateThreshold float64 // 相似度达到该值（0-1）的题目视为重复
+
+	// 分批生成配置
+	GenerateMaxCount        int // 单次请求最多生成的题目数量
+	GenerateChunkSize       int // 每批（每次AI调用）生成的题目数量
+	GenerateConcurrency     int // 全局同时进行的AI调用批次上限
+	GenerateUserConcurrency int // 单个用户同时进行的AI调用批次上限
+
+	// 按资料出题配置
+	SourceMaxBytes   int // 单份资料的大小上限（字节）
+	SourceChunkBytes int // 资料拆分后每段的大小上限（字节，每段单独作为一次AI调用的出题依据）
+
+	// 按本地仓库出题配置
+	SourceRepoRoots    []string // 允许登记的仓库所在目录（绝对路径，为空时不能按本地仓库出题）
+	SourceRepoMaxFiles int      // 单个仓库最多扫描的文件数量
+
+	// 生成配额配置（按角色的默认配额，可为单个用户单独设置）
+	UserQuota  QuotaLimits // user 角色的默认配额
+	AdminQuota QuotaLimits // admin 角色的默认配额
+
+	QuotaTokensPerQuestion int64 // 每道题目预估消耗的 token 数（生成前按请求数量预占 token 配额）
+
+	// 预览有效期配置
+	PreviewTTLHours        int // 临时题目的有效期（小时），超过后未确认的预览过期
+	PreviewRetentionHours  int // 已确认或已过期的临时题目保留多久（小时）后彻底删除
+	JanitorIntervalMinutes int // 清理任务的执行间隔（分钟）
+
+	// 异步生成任务配置
+	JobWorkers        int // 工作协程数量
+	JobQueueSize      int // 任务队列长度（超出时拒绝新任务）
+	JobTimeoutSeconds int // 单个任务的最长执行时间（秒）
+
 	// 数据库配置
 	DBPath string // 数据库文件路径
 
@@ -44,12 +97,70 @@ func LoadConfig() (*Config, error) {
 
 	// 解析配置项（带默认值处理）
 	cfg := &Config{
-		// AI API 配置（无默认值，必须在 .env 或 .env.local 中定义）
-		TongyiAPIKey:   getEnv("TONGYI_API_KEY", ""),
-		DeepseekAPIKey: getEnv("DEEPSEEK_API_KEY", ""),
-
 		// 支持的编程语言（默认空切片，解析为 []string）
-		SupportedLanguages: parseLanguages(getEnv("SUPPORTED_LANGUAGES", "")),
+		SupportedLanguages: parseList(getEnv("SUPPORTED_LANGUAGES", "")),
+
+		// AI 调用容错配置（默认超时 60 秒，重试 2 次，连续失败 5 次熔断 30 秒）
+		AITimeoutSeconds:         getEnvAsInt("AI_TIMEOUT_SECONDS", 60),
+		AIMaxRetries:             getEnvAsInt("AI_MAX_RETRIES", 2),
+		AIRetryBaseMillis:        getEnvAsInt("AI_RETRY_BASE_MS", 500),
+		AIBreakerThreshold:       getEnvAsInt("AI_BREAKER_THRESHOLD", 5),
+		AIBreakerCooldownSeconds: getEnvAsInt("AI_BREAKER_COOLDOWN_SECONDS", 30),
+
+		// AI 模型降级配置（默认不降级）
+		AIFallbackModels: parseList(getEnv("AI_FALLBACK_MODELS", "")),
+
+		// AI 响应缓存配置（默认不启用，最多缓存 1000 条）
+		AICacheTTLSeconds: getEnvAsInt("AI_CACHE_TTL_SECONDS", 0),
+		AICacheMaxEntries: getEnvAsInt("AI_CACHE_MAX_ENTRIES", 1000),
+
+		// 题目校验配置（默认修正 1 次）
+		AIMaxReprompts: getEnvAsInt("AI_MAX_REPROMPTS", 1),
+
+		// 答案校验配置（默认不指定校验模型）
+		AIVerifyModel: getEnv("AI_VERIFY_MODEL", ""),
+
+		// 近似重复检测配置（默认相似度 0.8 以上视为重复）
+		DuplicateThreshold: getEnvAsFloat("DUPLICATE_THRESHOLD", 0.8),
+
+		// 分批生成配置（默认最多 300 道，每批 10 道，全局并发 8 批，单用户并发 2 批）
+		GenerateMaxCount:        getEnvAsInt("GENERATE_MAX_COUNT", 300),
+		GenerateChunkSize:       getEnvAsInt("GENERATE_CHUNK_SIZE", 10),
+		GenerateConcurrency:     getEnvAsInt("GENERATE_CONCURRENCY", 8),
+		GenerateUserConcurrency: getEnvAsInt("GENERATE_USER_CONCURRENCY", 2),
+
+		// 按资料出题配置（默认单份资料最大 256KB，每段最大 8KB）
+		SourceMaxBytes:   getEnvAsInt("SOURCE_MAX_BYTES", 256*1024),
+		SourceChunkBytes: getEnvAsInt("SOURCE_CHUNK_BYTES", 8*1024),
+
+		// 按本地仓库出题配置（默认不允许任何目录，单个仓库最多扫描 5000 个文件）
+		SourceRepoRoots:    parseList(getEnv("SOURCE_REPO_ROOTS", "")),
+		SourceRepoMaxFiles: getEnvAsInt("SOURCE_REPO_MAX_FILES", 5000),
+
+		// 生成配额配置（默认普通用户每天 200 道、每月 3000 道题目，token 不限；管理员不限；每道题目预估 500 token）
+		UserQuota: QuotaLimits{
+			DailyQuestions:   getEnvAsInt64("QUOTA_USER_DAILY_QUESTIONS", 200),
+			MonthlyQuestions: getEnvAsInt64("QUOTA_USER_MONTHLY_QUESTIONS", 3000),
+			DailyTokens:      getEnvAsInt64("QUOTA_USER_DAILY_TOKENS", 0),
+			MonthlyTokens:    getEnvAsInt64("QUOTA_USER_MONTHLY_TOKENS", 0),
+		},
+		AdminQuota: QuotaLimits{
+			DailyQuestions:   getEnvAsInt64("QUOTA_ADMIN_DAILY_QUESTIONS", 0),
+			MonthlyQuestions: getEnvAsInt64("QUOTA_ADMIN_MONTHLY_QUESTIONS", 0),
+			DailyTokens:      getEnvAsInt64("QUOTA_ADMIN_DAILY_TOKENS", 0),
+			MonthlyTokens:    getEnvAsInt64("QUOTA_ADMIN_MONTHLY_TOKENS", 0),
+		},
+		QuotaTokensPerQuestion: getEnvAsInt64("QUOTA_TOKENS_PER_QUESTION", 500),
+
+		// 预览有效期配置（默认 72 小时过期，软删除后保留 7 天，每小时清理一次）
+		PreviewTTLHours:        getEnvAsInt("PREVIEW_TTL_HOURS", 72),
+		PreviewRetentionHours:  getEnvAsInt("PREVIEW_RETENTION_HOURS", 168),
+		JanitorIntervalMinutes: getEnvAsInt("JANITOR_INTERVAL_MINUTES", 60),
+
+		// 异步生成任务配置（默认 4 个工作协程，队列长度 100，单任务最长 10 分钟）
+		JobWorkers:        getEnvAsInt("JOB_WORKERS", 4),
+		JobQueueSize:      getEnvAsInt("JOB_QUEUE_SIZE", 100),
+		JobTimeoutSeconds: getEnvAsInt("JOB_TIMEOUT_SECONDS", 600),
 
 		// 数据库配置（默认当前目录下的 exam_system.db）
 		DBPath: getEnv("DB_PATH", "./exam_system.db"),
@@ -63,6 +174,13 @@ func LoadConfig() (*Config, error) {
 		GinMode:    getEnv("GIN_MODE", "debug"),
 	}
 
+	// AI 调用单价（格式 模型=提示语单价/生成单价，单位为每千 token）
+	prices, err := parsePrices(getEnv("AI_PRICES", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg.AIPrices = prices
+
 	// 验证必要的配置项（避免程序启动后因缺失关键配置出错）
 	if err := cfg.validate(); err != nil {
 		return nil, err
@@ -71,6 +189,65 @@ func LoadConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// AIPrice 模型的 token 单价（每千 token）
+type AIPrice struct {
+	Prompt     float64 // 提示语单价
+	Completion float64 // 生成内容单价
+}
+
+// Cost 按单价估算一次调用的费用
+func (p AIPrice) Cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*p.Prompt + float64(completionTokens)*p.Completion) / 1000
+}
+
+// QuotaLimits 生成配额（0 表示不限）
+type QuotaLimits struct {
+	DailyQuestions   int64 `json:"daily_questions"`   // 每天最多生成的题目数量
+	MonthlyQuestions int64 `json:"monthly_questions"` // 每月最多生成的题目数量
+	DailyTokens      int64 `json:"daily_tokens"`      // 每天最多消耗的 token 数
+	MonthlyTokens    int64 `json:"monthly_tokens"`    // 每月最多消耗的 token 数
+}
+
+// RoleQuota 返回角色的默认配额（admin 以外的角色按 user 处理）
+func (c *Config) RoleQuota(role string) QuotaLimits {
+	if role == "admin" {
+		return c.AdminQuota
+	}
+	return c.UserQuota
+}
+
+// AIModelConfig 单个AI模型的配置（环境变量以模型名大写为前缀，如 DEEPSEEK_API_KEY）
+type AIModelConfig struct {
+	Name    string // 模型名（即请求中的 ai_model）
+	APIKey  string // API 密钥（<PREFIX>_API_KEY）
+	BaseURL string // 接口地址（<PREFIX>_BASE_URL，为空时使用模型默认地址）
+	Model   string // 厂商侧模型名（<PREFIX>_MODEL，为空时使用模型默认值）
+
+	Headers map[string]string // 额外请求头（<PREFIX>_HEADERS，格式 Key:Value,Key2:Value2）
+}
+
+// AIModel 读取指定AI模型的配置，新增模型无需修改 Config 结构体
+func (c *Config) AIModel(name string) AIModelConfig {
+	return AIModelConfig{
+		Name:    name,
+		APIKey:  getEnv(aiModelEnvKey(name, "API_KEY"), ""),
+		BaseURL: getEnv(aiModelEnvKey(name, "BASE_URL"), ""),
+		Model:   getEnv(aiModelEnvKey(name, "MODEL"), ""),
+		Headers: parseHeaders(getEnv(aiModelEnvKey(name, "HEADERS"), "")),
+	}
+}
+
+// Get 读取AI模型的扩展配置项（<PREFIX>_<KEY>），供各模型自定义参数使用
+func (m AIModelConfig) Get(key, defaultValue string) string {
+	return getEnv(aiModelEnvKey(m.Name, key), defaultValue)
+}
+
+// 工具函数：拼接AI模型配置的环境变量名（如 deepseek + API_KEY -> DEEPSEEK_API_KEY）
+func aiModelEnvKey(name, key string) string {
+	prefix := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	return prefix + "_" + key
+}
+
 // 工具函数：获取环境变量，不存在则返回默认值
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -92,23 +269,86 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
-// 工具函数：将逗号分隔的字符串解析为编程语言切片
-func parseLanguages(langsStr string) []string {
-	if langsStr == "" {
+// 工具函数：将环境变量解析为 int64 类型
+func getEnvAsInt64(key string, defaultValue int64) int64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		return defaultValue // 解析失败时返回默认值
+	}
+	return value
+}
+
+// 工具函数：将环境变量解析为 float64 类型
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue // 解析失败时返回默认值
+	}
+	return value
+}
+
+// 工具函数：将逗号分隔的字符串解析为切片（如编程语言、模型列表）
+func parseList(listStr string) []string {
+	if listStr == "" {
 		return []string{}
 	}
 	// 去除空格并分割（处理类似 "Go, Python, Java" 带空格的情况）
-	langs := strings.Split(strings.ReplaceAll(langsStr, " ", ""), ",")
-	return langs
+	items := strings.Split(strings.ReplaceAll(listStr, " ", ""), ",")
+	return items
 }
 
-// 验证配置项的合法性
-func (c *Config) validate() error {
-	// 验证 AI API 密钥（至少需要一个 API 密钥，根据你的业务需求调整）
-	if c.TongyiAPIKey == "" && c.DeepseekAPIKey == "" {
-		return fmt.Errorf("至少需要配置 TONGYI_API_KEY 或 DEEPSEEK_API_KEY")
+// 工具函数：将 "Key:Value,Key2:Value2" 格式的字符串解析为请求头映射
+func parseHeaders(headersStr string) map[string]string {
+	headers := make(map[string]string)
+	if headersStr == "" {
+		return headers
+	}
+	for _, pair := range strings.Split(headersStr, ",") {
+		key, value, found := strings.Cut(pair, ":")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			continue // 忽略格式错误的项
+		}
+		headers[key] = strings.TrimSpace(value)
 	}
+	return headers
+}
 
+// 工具函数：将 "deepseek=0.002/0.008,tongyi=0.0008/0.002" 格式的字符串解析为各模型的单价
+func parsePrices(pricesStr string) (map[string]AIPrice, error) {
+	prices := make(map[string]AIPrice)
+	if pricesStr == "" {
+		return prices, nil
+	}
+	for _, item := range strings.Split(strings.ReplaceAll(pricesStr, " ", ""), ",") {
+		if item == "" {
+			continue
+		}
+		model, price, found := strings.Cut(item, "=")
+		promptStr, completionStr, ok := strings.Cut(price, "/")
+		if !found || model == "" || !ok {
+			return nil, fmt.Errorf("AI_PRICES 格式错误（应为 模型=提示语单价/生成单价）: %s", item)
+		}
+		prompt, err1 := strconv.ParseFloat(promptStr, 64)
+		completion, err2 := strconv.ParseFloat(completionStr, 64)
+		if err1 != nil || err2 != nil || prompt < 0 || completion < 0 {
+			return nil, fmt.Errorf("AI_PRICES 单价必须是非负数: %s", item)
+		}
+		prices[model] = AIPrice{Prompt: prompt, Completion: completion}
+	}
+	return prices, nil
+}
+
+// 验证配置项的合法性
+func (c *Config) validate() error {
 	// 验证 JWT 密钥（生产环境必须配置，避免使用默认空值）
 	if c.JWTSecret == "" {
 		return fmt.Errorf("JWT_SECRET 不能为空，请配置密钥")
@@ -119,6 +359,70 @@ func (c *Config) validate() error {
 		return fmt.Errorf("SERVER_PORT 必须在 1-65535 之间，当前值: %d", c.ServerPort)
 	}
 
+	// 验证 AI 调用容错配置
+	if c.AITimeoutSeconds <= 0 {
+		return fmt.Errorf("AI_TIMEOUT_SECONDS 必须大于 0，当前值: %d", c.AITimeoutSeconds)
+	}
+	if c.AIMaxRetries < 0 {
+		return fmt.Errorf("AI_MAX_RETRIES 不能为负数，当前值: %d", c.AIMaxRetries)
+	}
+	if c.AIRetryBaseMillis < 0 {
+		return fmt.Errorf("AI_RETRY_BASE_MS 不能为负数，当前值: %d", c.AIRetryBaseMillis)
+	}
+	if c.AIBreakerCooldownSeconds < 0 {
+		return fmt.Errorf("AI_BREAKER_COOLDOWN_SECONDS 不能为负数，当前值: %d", c.AIBreakerCooldownSeconds)
+	}
+	if c.AIBreakerThreshold <= 0 {
+		return fmt.Errorf("AI_BREAKER_THRESHOLD 必须大于 0，当前值: %d", c.AIBreakerThreshold)
+	}
+	if c.AICacheTTLSeconds < 0 || c.AICacheMaxEntries <= 0 {
+		return fmt.Errorf("AI_CACHE_TTL_SECONDS 不能为负数，AI_CACHE_MAX_ENTRIES 必须大于 0")
+	}
+	if c.AIMaxReprompts < 0 {
+		return fmt.Errorf("AI_MAX_REPROMPTS 不能为负数，当前值: %d", c.AIMaxReprompts)
+	}
+	if c.DuplicateThreshold <= 0 || c.DuplicateThreshold > 1 {
+		return fmt.Errorf("DUPLICATE_THRESHOLD 必须在 (0, 1] 范围内，当前值: %g", c.DuplicateThreshold)
+	}
+
+	// 验证分批生成配置
+	if c.GenerateMaxCount <= 0 || c.GenerateChunkSize <= 0 || c.GenerateConcurrency <= 0 || c.GenerateUserConcurrency <= 0 {
+		return fmt.Errorf("GENERATE_MAX_COUNT、GENERATE_CHUNK_SIZE、GENERATE_CONCURRENCY、GENERATE_USER_CONCURRENCY 必须大于 0")
+	}
+
+	// 验证按资料出题配置
+	if c.SourceMaxBytes <= 0 || c.SourceChunkBytes <= 0 {
+		return fmt.Errorf("SOURCE_MAX_BYTES、SOURCE_CHUNK_BYTES 必须大于 0")
+	}
+	for _, root := range c.SourceRepoRoots {
+		if !filepath.IsAbs(root) {
+			return fmt.Errorf("SOURCE_REPO_ROOTS 必须是绝对路径，当前值: %s", root)
+		}
+	}
+	if c.SourceRepoMaxFiles <= 0 {
+		return fmt.Errorf("SOURCE_REPO_MAX_FILES 必须大于 0，当前值: %d", c.SourceRepoMaxFiles)
+	}
+
+	// 验证预览有效期配置
+	if c.PreviewTTLHours <= 0 || c.PreviewRetentionHours <= 0 || c.JanitorIntervalMinutes <= 0 {
+		return fmt.Errorf("PREVIEW_TTL_HOURS、PREVIEW_RETENTION_HOURS、JANITOR_INTERVAL_MINUTES 必须大于 0")
+	}
+
+	// 验证生成配额配置（0 表示不限）
+	for _, quota := range []QuotaLimits{c.UserQuota, c.AdminQuota} {
+		if quota.DailyQuestions < 0 || quota.MonthlyQuestions < 0 || quota.DailyTokens < 0 || quota.MonthlyTokens < 0 {
+			return fmt.Errorf("QUOTA_* 配额不能为负数")
+		}
+	}
+	if c.QuotaTokensPerQuestion <= 0 {
+		return fmt.Errorf("QUOTA_TOKENS_PER_QUESTION 必须大于 0，当前值: %d", c.QuotaTokensPerQuestion)
+	}
+
+	// 验证异步生成任务配置
+	if c.JobWorkers <= 0 || c.JobQueueSize <= 0 || c.JobTimeoutSeconds <= 0 {
+		return fmt.Errorf("JOB_WORKERS、JOB_QUEUE_SIZE、JOB_TIMEOUT_SECONDS 必须大于 0")
+	}
+
 	// 验证 Gin 模式（只能是 debug/release/test）
 	validGinModes := map[string]bool{
 		"debug":   true,
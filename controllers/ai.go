@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"CodeQuizAI/ai"
+	"CodeQuizAI/services"
+	"CodeQuizAI/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+)
+
+// GetAIModels 查询当前可用的AI模型列表
+func GetAIModels(c *gin.Context) {
+	utils.SendResponse(c, 200, "查询成功", gin.H{
+		"models": services.AvailableAIModels(),
+	})
+}
+
+// GetAIBreakers 查询各AI模型的熔断器状态（管理员）
+func GetAIBreakers(c *gin.Context) {
+	utils.SendResponse(c, 200, "查询成功", gin.H{
+		"breakers": services.GetAIBreakerStates(),
+	})
+}
+
+// GetAIUsage 按用户、模型或日期汇总AI调用的用量和费用（管理员）
+func GetAIUsage(c *gin.Context) {
+	// 1. 解析查询参数
+	var query services.UsageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.SendResponse(c, 400, "参数错误："+err.Error(), nil)
+		return
+	}
+
+	// 2. 调用服务层汇总
+	report, err := services.GetUsageReport(c.Request.Context(), query)
+	if err != nil {
+		utils.SendResponse(c, 500, "查询用量失败："+err.Error(), nil)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SendResponse(c, 200, "查询成功", report)
+}
+
+// aiErrorStatus 根据AI调用错误返回对应的HTTP状态码
+func aiErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ai.ErrUnsupportedModel):
+		return 400
+	case errors.Is(err, ai.ErrCircuitOpen):
+		return 503
+	case errors.Is(err, ai.ErrTimeout):
+		return 504
+	case errors.Is(err, utils.ErrSourceNotFound):
+		return 404
+	case errors.Is(err, utils.ErrRepositoryDisabled), errors.Is(err, utils.ErrRepositoryNotAllowed):
+		return 403
+	case errors.Is(err, utils.ErrRepositoryTooLarge):
+		return 413
+	}
+
+	var apiErr *ai.APIError
+	if errors.As(err, &apiErr) {
+		return 502
+	}
+	return 500
+}
@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"CodeQuizAI/config"
+	"CodeQuizAI/services"
+	"CodeQuizAI/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"time"
+)
+
+// ListPreviews 分页查询当前用户未确认且未过期的预览（题目数量和生成时长）
+func ListPreviews(c *gin.Context) {
+	// 1. 解析查询参数并设置默认值
+	var req services.ListPreviewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.SendResponse(c, 400, "参数错误："+err.Error(), nil)
+		return
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 || req.PageSize > 50 {
+		req.PageSize = 10
+	}
+
+	// 2. 获取当前登录用户ID
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+
+	// 3. 调用服务层查询
+	result, err := services.ListPreviews(c.Request.Context(), userIDInt64, req, time.Duration(cfg.PreviewTTLHours)*time.Hour)
+	if err != nil {
+		utils.SendResponse(c, 500, err.Error(), nil)
+		return
+	}
+
+	// 4. 返回响应
+	utils.SendResponse(c, 200, "查询成功", result)
+}
+
+// GetPreview 查询预览中未确认的临时题目（如页面刷新后重新获取）
+func GetPreview(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+
+	preview, err := services.GetPreview(c.Request.Context(), c.Param("preview_id"), userIDInt64, time.Duration(cfg.PreviewTTLHours)*time.Hour)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrPreviewNotFound):
+			utils.SendResponse(c, 404, err.Error(), nil)
+		case errors.Is(err, utils.ErrPreviewExpired):
+			utils.SendResponse(c, 410, err.Error(), nil)
+		default:
+			utils.SendResponse(c, 500, err.Error(), nil)
+		}
+		return
+	}
+	utils.SendResponse(c, 200, "查询成功", preview)
+}
+
+// DeletePreview 丢弃预览中所有未确认的临时题目
+func DeletePreview(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+
+	count, err := services.DeletePreview(c.Request.Context(), c.Param("preview_id"), userIDInt64)
+	if err != nil {
+		if errors.Is(err, utils.ErrPreviewNotFound) {
+			utils.SendResponse(c, 404, err.Error(), nil)
+		} else {
+			utils.SendResponse(c, 500, err.Error(), nil)
+		}
+		return
+	}
+	utils.SendResponse(c, 200, "预览已丢弃", gin.H{"count": count})
+}
+
+// UpdateTempQuestion 编辑预览中的单道临时题目（只修改传入的字段，原版本可撤销）
+func UpdateTempQuestion(c *gin.Context) {
+	// 1. 解析请求参数
+	var req services.TempQuestionEdit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendResponse(c, 400, "参数错误："+err.Error(), nil)
+		return
+	}
+	if req == (services.TempQuestionEdit{}) {
+		utils.SendResponse(c, 400, "没有需要修改的内容", nil)
+		return
+	}
+
+	// 2. 调用服务层编辑
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+	question, err := services.UpdateTempQuestion(c.Request.Context(), c.Param("preview_id"), c.Param("temp_id"), userIDInt64, req, time.Duration(cfg.PreviewTTLHours)*time.Hour)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrTempQuestionNotFound):
+			utils.SendResponse(c, 404, err.Error(), nil)
+		case errors.Is(err, utils.ErrPreviewExpired):
+			utils.SendResponse(c, 410, err.Error(), nil)
+		case errors.Is(err, utils.ErrInvalidEdit):
+			utils.SendResponse(c, 400, err.Error(), nil)
+		case errors.Is(err, utils.ErrRevisionConflict):
+			utils.SendResponse(c, 409, err.Error(), nil)
+		default:
+			utils.SendResponse(c, 500, "编辑题目失败："+err.Error(), nil)
+		}
+		return
+	}
+
+	// 3. 返回编辑后的题目
+	utils.SendResponse(c, 200, "修改成功", question)
+}
+
+// RegenerateTempQuestion 重新生成预览中的单道临时题目（temp_id 不变，原版本可撤销）
+func RegenerateTempQuestion(c *gin.Context) {
+	// 1. 解析请求参数（修改要求可选，请求体可为空）
+	var req services.RegenerateQuestionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendResponse(c, 400, "参数错误："+err.Error(), nil)
+			return
+		}
+	}
+
+	// 2. 获取当前登录用户ID，检查校验模型和生成配额（按1道题目计）
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+	if err := services.CheckVerifyModel(services.GenerateQuestionRequest{Verify: req.Verify, VerifyModel: req.VerifyModel}, cfg); err != nil {
+		utils.SendResponse(c, 400, err.Error(), nil)
+		return
+	}
+	reservation, ok := reserveQuota(c, userIDInt64, 1, cfg)
+	if !ok {
+		return
+	}
+	defer reservation.Release()
+
+	// 3. 调用服务层重新生成
+	result, err := services.RegenerateTempQuestion(
+		c.Request.Context(),
+		c.Param("preview_id"),
+		c.Param("temp_id"),
+		userIDInt64,
+		req,
+		cfg,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrTempQuestionNotFound):
+			utils.SendResponse(c, 404, err.Error(), nil)
+		case errors.Is(err, utils.ErrPreviewExpired):
+			utils.SendResponse(c, 410, err.Error(), nil)
+		case errors.Is(err, utils.ErrRevisionConflict), errors.Is(err, utils.ErrDuplicateRegenerated):
+			utils.SendResponse(c, 409, err.Error(), nil)
+		default:
+			utils.SendResponse(c, aiErrorStatus(err), "重新生成题目失败："+err.Error(), nil)
+		}
+		return
+	}
+
+	// 4. 返回替换后的题目
+	utils.SendResponse(c, 200, "题目已重新生成", result)
+}
+
+// UndoTempQuestion 撤销最近一次重新生成或编辑，恢复到上一个版本
+func UndoTempQuestion(c *gin.Context) {
+	// 1. 获取当前登录用户ID
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+
+	// 2. 调用服务层撤销
+	question, err := services.UndoTempQuestion(c.Request.Context(), c.Param("preview_id"), c.Param("temp_id"), userIDInt64, time.Duration(cfg.PreviewTTLHours)*time.Hour)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrTempQuestionNotFound):
+			utils.SendResponse(c, 404, err.Error(), nil)
+		case errors.Is(err, utils.ErrPreviewExpired):
+			utils.SendResponse(c, 410, err.Error(), nil)
+		case errors.Is(err, utils.ErrNoPreviousRevision), errors.Is(err, utils.ErrRevisionConflict):
+			utils.SendResponse(c, 409, err.Error(), nil)
+		default:
+			utils.SendResponse(c, 500, "撤销失败："+err.Error(), nil)
+		}
+		return
+	}
+
+	// 3. 返回恢复后的题目
+	utils.SendResponse(c, 200, "已恢复上一个版本", question)
+}
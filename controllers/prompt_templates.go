@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"CodeQuizAI/services"
+	"CodeQuizAI/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+// ListPromptTemplates 查询提示语模板及其版本（管理员）
+func ListPromptTemplates(c *gin.Context) {
+	templates, err := services.ListPromptTemplates(c.Request.Context(), c.Query("name"))
+	if err != nil {
+		utils.SendResponse(c, 500, err.Error(), nil)
+		return
+	}
+	utils.SendResponse(c, 200, "查询成功", gin.H{"list": templates})
+}
+
+// CreatePromptTemplate 新增提示语模板版本（管理员）
+func CreatePromptTemplate(c *gin.Context) {
+	// 1. 解析请求参数
+	var req services.CreatePromptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendResponse(c, 400, "参数错误："+err.Error(), nil)
+		return
+	}
+
+	// 2. 调用服务层保存（模板语法错误或题型不支持返回400）
+	template, err := services.CreatePromptTemplate(c.Request.Context(), req)
+	if err != nil {
+		utils.SendResponse(c, 400, "新增模板失败："+err.Error(), nil)
+		return
+	}
+	utils.SendResponse(c, 200, "模板已保存", template)
+}
+
+// PreviewPromptTemplate 使用示例参数渲染指定模板版本（管理员）
+func PreviewPromptTemplate(c *gin.Context) {
+	// 1. 解析路径参数和请求参数（请求体可为空）
+	templateID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.SendResponse(c, 400, "无效的模板ID", nil)
+		return
+	}
+	var req services.PreviewPromptTemplateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendResponse(c, 400, "参数错误："+err.Error(), nil)
+			return
+		}
+	}
+
+	// 2. 渲染模板
+	prompt, err := services.PreviewPromptTemplate(c.Request.Context(), templateID, req)
+	if err != nil {
+		if errors.Is(err, utils.ErrTemplateNotFound) {
+			utils.SendResponse(c, 404, err.Error(), nil)
+		} else {
+			utils.SendResponse(c, 400, "渲染失败："+err.Error(), nil)
+		}
+		return
+	}
+	utils.SendResponse(c, 200, "渲染成功", gin.H{"prompt": prompt})
+}
+
+// ActivatePromptTemplate 激活指定模板版本（管理员）
+func ActivatePromptTemplate(c *gin.Context) {
+	templateID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.SendResponse(c, 400, "无效的模板ID", nil)
+		return
+	}
+
+	template, err := services.ActivatePromptTemplate(c.Request.Context(), templateID)
+	if err != nil {
+		if errors.Is(err, utils.ErrTemplateNotFound) {
+			utils.SendResponse(c, 404, err.Error(), nil)
+		} else {
+			utils.SendResponse(c, 500, err.Error(), nil)
+		}
+		return
+	}
+	utils.SendResponse(c, 200, "模板已激活", template)
+}
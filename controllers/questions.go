@@ -6,16 +6,27 @@ import (
 	"CodeQuizAI/services"
 	"CodeQuizAI/utils"
 	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"log"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // GenerateQuestionResponse 生成题目的响应数据
 type GenerateQuestionResponse struct {
-	PreviewID string                `json:"preview_id"` // 预览批次ID
-	Questions []models.TempQuestion `json:"questions"`  // 生成的临时题目
+	PreviewID    string                        `json:"preview_id"`              // 预览批次ID
+	Questions    []models.TempQuestion         `json:"questions"`               // 生成的临时题目
+	ParseErrors  []services.ItemParseError     `json:"parse_errors,omitempty"`  // 解析失败被跳过的题目
+	Rejected     []services.RejectedQuestion   `json:"rejected,omitempty"`      // 校验未通过（修正后仍不合格）的题目
+	AIModel      string                        `json:"ai_model"`                // 实际生成题目的模型
+	FallbackUsed bool                          `json:"fallback_used"`           // 是否发生了模型降级
+	Attempts     []services.ModelAttempt       `json:"attempts"`                // 各模型的尝试记录
+	FailedChunks []services.ChunkFailure       `json:"failed_chunks,omitempty"` // 生成失败的批次（分批生成时）
+	Deduplicated int                           `json:"deduplicated,omitempty"`  // 与其他批次重复而被去掉的题目数量
+	Verification *services.VerificationSummary `json:"verification,omitempty"`  // 答案校验结果（开启校验时）
 }
 
 // GenerateQuestions 处理题目生成请求
@@ -40,10 +51,52 @@ func GenerateQuestions(c *gin.Context) {
 		utils.SendResponse(c, 400, "不支持的编程语言："+req.Language, nil)
 		return
 	}
+	if !services.IsValidQuestionType(req.QuestionType) {
+		utils.SendResponse(c, 400, "不支持的题型："+req.QuestionType+"（可选 "+strings.Join(services.QuestionTypes(), "/")+"）", nil)
+		return
+	}
+	if req.Count > cfg.GenerateMaxCount {
+		utils.SendResponse(c, 400, fmt.Sprintf("生成数量不能超过 %d", cfg.GenerateMaxCount), nil)
+		return
+	}
+	if err := services.CheckVerifyModel(req, cfg); err != nil {
+		utils.SendResponse(c, 400, err.Error(), nil)
+		return
+	}
+	if req.MockFailure != "" && req.AIModel != "mock" {
+		utils.SendResponse(c, 400, "mock_failure 只能在 ai_model 为 mock 时使用", nil)
+		return
+	}
+	if !checkSource(c, userIDInt64, req.SourceID) {
+		return
+	}
+	reservation, ok := reserveQuota(c, userIDInt64, req.Count, cfg)
+	if !ok {
+		return
+	}
 
-	// 4. 调用服务层生成题目
+	// 4. 异步模式：提交任务后立即返回任务ID，通过 GET /api/questions/jobs/:id 查询结果（配额预占由任务持有）
 	previewID := uuid.New().String() // 生成预览批次ID
-	tempQuestions, err := services.GenerateQuestions(
+	if c.Query("async") == "true" {
+		job, err := services.SubmitGenerationJob(c.Request.Context(), previewID, userIDInt64, req, reservation)
+		if err != nil {
+			if errors.Is(err, utils.ErrJobQueueFull) {
+				utils.SendResponse(c, 503, err.Error(), nil)
+			} else {
+				utils.SendResponse(c, 500, "提交生成任务失败："+err.Error(), nil)
+			}
+			return
+		}
+		utils.SendResponse(c, 202, "生成任务已提交", gin.H{
+			"job_id": job.ID,
+			"status": job.Status,
+		})
+		return
+	}
+
+	// 5. 同步模式：调用服务层生成题目，结束后结算配额预占
+	defer reservation.Release()
+	result, err := services.GenerateQuestions(
 		c.Request.Context(),
 		previewID,
 		userIDInt64,
@@ -51,19 +104,165 @@ func GenerateQuestions(c *gin.Context) {
 		cfg,
 	)
 	if err != nil {
-		utils.SendResponse(c, 500, "生成题目失败："+err.Error(), nil)
+		utils.SendResponse(c, aiErrorStatus(err), "生成题目失败："+err.Error(), nil)
 		return
 	}
 
-	// 5. 返回成功响应
+	// 6. 返回成功响应
 	data := GenerateQuestionResponse{
-		PreviewID: previewID,
-		Questions: tempQuestions,
+		PreviewID:    previewID,
+		Questions:    result.Questions,
+		ParseErrors:  result.ParseErrors,
+		Rejected:     result.Rejected,
+		AIModel:      result.AIModel,
+		FallbackUsed: result.FallbackUsed,
+		Attempts:     result.Attempts,
+		FailedChunks: result.FailedChunks,
+		Deduplicated: result.Deduplicated,
+		Verification: result.Verification,
+	}
+	message := "题目生成成功"
+	if len(result.FailedChunks) > 0 {
+		message = fmt.Sprintf("题目部分生成成功（%d 批失败）", len(result.FailedChunks))
 	}
-	utils.SendResponse(c, 200, "题目生成成功", data)
+	utils.SendResponse(c, 200, message, data)
 }
 
-// ConfirmQuestions 处理题目确认入库请求
+// GenerateQuestionsStream 流式生成题目（Server-Sent Events）：
+// 依次推送 preview（批次ID）、question（每道已保存的临时题目）、done（生成结果）或 error（失败原因）事件；
+// 开启答案校验时，在 done 之前为每道题目推送 verified（更新了校验结果的临时题目）事件
+func GenerateQuestionsStream(c *gin.Context) {
+	// 1. 解析并验证请求参数
+	var req services.GenerateQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendResponse(c, 400, "参数错误："+err.Error(), nil)
+		return
+	}
+
+	// 2. 获取当前登录用户ID（从JWT中间件上下文）
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+
+	// 3. 验证编程语言是否在配置的支持列表中
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+	if !services.IsLanguageSupported(req.Language, cfg.SupportedLanguages) {
+		utils.SendResponse(c, 400, "不支持的编程语言："+req.Language, nil)
+		return
+	}
+	if !services.IsValidQuestionType(req.QuestionType) {
+		utils.SendResponse(c, 400, "不支持的题型："+req.QuestionType+"（可选 "+strings.Join(services.QuestionTypes(), "/")+"）", nil)
+		return
+	}
+	if req.Count > cfg.GenerateChunkSize {
+		utils.SendResponse(c, 400, fmt.Sprintf("流式生成数量不能超过 %d，更多题目请使用普通或异步生成", cfg.GenerateChunkSize), nil)
+		return
+	}
+	if req.SourceID > 0 {
+		utils.SendResponse(c, 400, "流式生成不支持按资料出题，请使用普通或异步生成", nil)
+		return
+	}
+	if err := services.CheckVerifyModel(req, cfg); err != nil {
+		utils.SendResponse(c, 400, err.Error(), nil)
+		return
+	}
+	if req.MockFailure != "" && req.AIModel != "mock" {
+		utils.SendResponse(c, 400, "mock_failure 只能在 ai_model 为 mock 时使用", nil)
+		return
+	}
+	reservation, ok := reserveQuota(c, userIDInt64, req.Count, cfg)
+	if !ok {
+		return
+	}
+	defer reservation.Release()
+
+	// 4. 设置SSE响应头，先推送预览批次ID
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 禁用反向代理缓冲
+	previewID := uuid.New().String()
+	sendEvent := func(event string, data interface{}) {
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+	}
+	sendEvent("preview", gin.H{"preview_id": previewID})
+
+	// 5. 流式生成：每保存一道题目推送一次
+	result, err := services.GenerateQuestionsStream(
+		c.Request.Context(),
+		previewID,
+		userIDInt64,
+		req,
+		cfg,
+		func(question models.TempQuestion) {
+			sendEvent("question", question)
+		},
+	)
+	if err != nil {
+		sendEvent("error", gin.H{
+			"preview_id":   previewID,
+			"count":        len(result.Questions), // 中断前已保存的题目仍可确认入库
+			"message":      "生成题目失败：" + err.Error(),
+			"parse_errors": result.ParseErrors,
+			"rejected":     result.Rejected,
+			"attempts":     result.Attempts,
+		})
+		return
+	}
+
+	// 6. 推送校验结果和完成事件
+	if result.Verification != nil {
+		for _, question := range result.Questions {
+			sendEvent("verified", question)
+		}
+	}
+	sendEvent("done", gin.H{
+		"preview_id":    previewID,
+		"count":         len(result.Questions),
+		"ai_model":      result.AIModel,
+		"fallback_used": result.FallbackUsed,
+		"parse_errors":  result.ParseErrors,
+		"rejected":      result.Rejected,
+		"attempts":      result.Attempts,
+		"verification":  result.Verification,
+	})
+}
+
+// GetGenerationJob 查询异步生成任务的状态和结果
+func GetGenerationJob(c *gin.Context) {
+	// 1. 解析路径参数（任务ID）
+	jobID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.SendResponse(c, 400, "无效的任务ID", nil)
+		return
+	}
+
+	// 2. 获取当前用户ID
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+
+	// 3. 调用服务层查询
+	result, err := services.GetGenerationJob(c.Request.Context(), jobID, userIDInt64)
+	if err != nil {
+		if errors.Is(err, utils.ErrJobNotFound) {
+			utils.SendResponse(c, 404, err.Error(), nil)
+		} else {
+			utils.SendResponse(c, 500, "查询生成任务失败："+err.Error(), nil)
+		}
+		return
+	}
+
+	// 4. 返回响应
+	utils.SendResponse(c, 200, "查询成功", result)
+}
+
+// idempotencyKeyMaxLength Idempotency-Key 请求头的最大长度
+const idempotencyKeyMaxLength = 255
+
+// ConfirmQuestions 处理题目确认入库请求（支持 Idempotency-Key 请求头，同一个键重复提交时返回首次请求的响应）
 func ConfirmQuestions(c *gin.Context) {
 	// 1. 解析请求参数
 	var req services.ConfirmQuestionsRequest
@@ -76,22 +275,109 @@ func ConfirmQuestions(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 	userIDInt64, _ := userID.(int64)
 
-	// 3. 调用服务层执行确认逻辑
+	// 3. 携带幂等键时，占用该键或直接返回首次请求的响应
+	key := strings.TrimSpace(c.GetHeader("Idempotency-Key"))
+	if len(key) > idempotencyKeyMaxLength {
+		utils.SendResponse(c, 400, fmt.Sprintf("Idempotency-Key 长度不能超过 %d", idempotencyKeyMaxLength), nil)
+		return
+	}
+	if key != "" {
+		saved, err := services.BeginIdempotentRequest(c.Request.Context(), userIDInt64, key, "questions.confirm", req)
+		switch {
+		case errors.Is(err, utils.ErrIdempotencyKeyInUse):
+			utils.SendResponse(c, 409, err.Error(), nil)
+			return
+		case errors.Is(err, utils.ErrIdempotencyKeyMismatch):
+			utils.SendResponse(c, 422, err.Error(), nil)
+			return
+		case err != nil:
+			utils.SendResponse(c, 500, "确认题目失败："+err.Error(), nil)
+			return
+		case saved != nil:
+			c.Header("Idempotent-Replayed", "true")
+			utils.SendResponse(c, saved.Code, saved.Message, saved.Data)
+			return
+		}
+	}
+
+	// 4. 需要跳过重复题目时确定相似度阈值（未指定时使用服务端配置）
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+	threshold := 0.0
+	if req.RejectDuplicates {
+		threshold = cfg.DuplicateThreshold
+		if req.DuplicateThreshold > 0 {
+			threshold = req.DuplicateThreshold
+		}
+	}
+
+	// 5. 调用服务层执行确认逻辑（失败时释放幂等键，客户端可以用同一个键重试）
 	result, err := services.ConfirmQuestions(
 		c.Request.Context(),
 		req.PreviewID,
 		req.Selected,
 		userIDInt64,
+		threshold,
+		time.Duration(cfg.PreviewTTLHours)*time.Hour,
 	)
 	if err != nil {
-		utils.SendResponse(c, 500, "确认题目失败："+err.Error(), nil)
+		if key != "" {
+			services.ReleaseIdempotentRequest(c.Request.Context(), userIDInt64, key)
+		}
+		switch {
+		case errors.Is(err, utils.ErrDuplicateSelection):
+			utils.SendResponse(c, 400, err.Error(), nil)
+		case errors.Is(err, utils.ErrTempQuestionsMissing):
+			utils.SendResponse(c, 404, err.Error(), nil)
+		case errors.Is(err, utils.ErrPreviewExpired):
+			utils.SendResponse(c, 410, err.Error(), nil)
+		case errors.Is(err, utils.ErrConfirmConflict):
+			utils.SendResponse(c, 409, err.Error(), nil)
+		default:
+			utils.SendResponse(c, 500, "确认题目失败："+err.Error(), nil)
+		}
 		return
 	}
 
-	// 4. 返回成功响应
+	// 6. 保存幂等键对应的响应，返回成功响应
+	if key != "" {
+		services.CompleteIdempotentRequest(c.Request.Context(), userIDInt64, key, 200, "题目已成功入库", result)
+	}
 	utils.SendResponse(c, 200, "题目已成功入库", result)
 }
 
+// GetDuplicateQuestions 扫描当前用户题库中的近似重复题目
+func GetDuplicateQuestions(c *gin.Context) {
+	// 1. 解析查询参数
+	var req services.FindDuplicatesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.SendResponse(c, 400, "参数错误："+err.Error(), nil)
+		return
+	}
+
+	// 2. 确定相似度阈值（未指定时使用服务端配置）
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+	threshold := cfg.DuplicateThreshold
+	if req.Threshold > 0 {
+		threshold = req.Threshold
+	}
+
+	// 3. 获取当前用户ID并扫描
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+	result, err := services.FindDuplicateClusters(c.Request.Context(), userIDInt64, req.Language, threshold)
+	if err != nil {
+		utils.SendResponse(c, 500, "扫描重复题目失败："+err.Error(), nil)
+		return
+	}
+	utils.SendResponse(c, 200, "查询成功", result)
+}
+
 // GetQuestions 查询当前用户的题目列表
 func GetQuestions(c *gin.Context) {
 	// 1. 解析查询参数
@@ -187,8 +473,12 @@ func HasAnyField(u services.UpdateQuestionRequest) bool {
 		u.QuestionType != "" ||
 		u.Options != "" ||
 		u.Answer != "" ||
+		u.CodeSnippet != "" ||
+		u.CodeLanguage != "" ||
 		u.Explanation != "" ||
-		u.Keywords != ""
+		u.Keywords != "" ||
+		u.Difficulty != "" ||
+		u.VerificationStatus != ""
 }
 
 // DeleteQuestion 软删除指定题目
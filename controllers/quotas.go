@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"CodeQuizAI/config"
+	"CodeQuizAI/services"
+	"CodeQuizAI/utils"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log"
+	"strconv"
+)
+
+// reserveQuota 生成前检查并预占配额，超出时返回 429（含各项配额的剩余量和重置时间）并返回 false；
+// 通过时返回的预占需在生成结束后调用 Release 结算
+func reserveQuota(c *gin.Context, userID int64, count int, cfg *config.Config) (*services.QuotaReservation, bool) {
+	reservation, status, err := services.ReserveQuota(c.Request.Context(), userID, count, cfg)
+	if err != nil {
+		if errors.Is(err, utils.ErrQuotaExceeded) {
+			utils.SendResponse(c, 429, err.Error(), status)
+		} else {
+			utils.SendResponse(c, 500, "检查生成配额失败："+err.Error(), nil)
+		}
+		return nil, false
+	}
+	return reservation, true
+}
+
+// GetMyQuota 查询当前用户的配额和用量
+func GetMyQuota(c *gin.Context) {
+	// 1. 获取当前用户ID
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+
+	// 2. 调用服务层查询
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+	status, err := services.GetQuotaStatus(c.Request.Context(), userIDInt64, cfg)
+	if err != nil {
+		utils.SendResponse(c, 500, "查询配额失败："+err.Error(), nil)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SendResponse(c, 200, "查询成功", status)
+}
+
+// ListQuotas 查询角色默认配额和单独设置了配额的用户（管理员）
+func ListQuotas(c *gin.Context) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+	overview, err := services.GetQuotaOverview(c.Request.Context(), cfg)
+	if err != nil {
+		utils.SendResponse(c, 500, "查询配额失败："+err.Error(), nil)
+		return
+	}
+	utils.SendResponse(c, 200, "查询成功", overview)
+}
+
+// GetUserQuota 查询指定用户的配额和用量（管理员）
+func GetUserQuota(c *gin.Context) {
+	// 1. 解析路径参数（用户ID）
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.SendResponse(c, 400, "无效的用户ID", nil)
+		return
+	}
+
+	// 2. 调用服务层查询
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+	status, err := services.GetQuotaStatus(c.Request.Context(), userID, cfg)
+	if err != nil {
+		if errors.Is(err, utils.ErrUserNotFound) {
+			utils.SendResponse(c, 404, err.Error(), nil)
+		} else {
+			utils.SendResponse(c, 500, "查询配额失败："+err.Error(), nil)
+		}
+		return
+	}
+
+	// 3. 返回响应
+	utils.SendResponse(c, 200, "查询成功", status)
+}
+
+// UpdateUserQuota 设置指定用户的单独配额（管理员）
+func UpdateUserQuota(c *gin.Context) {
+	// 1. 解析路径参数和请求体
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.SendResponse(c, 400, "无效的用户ID", nil)
+		return
+	}
+	var req services.UpdateQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendResponse(c, 400, "参数错误："+err.Error(), nil)
+		return
+	}
+
+	// 2. 调用服务层保存
+	quota, err := services.SetUserQuota(c.Request.Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, utils.ErrEmptyQuota) {
+			utils.SendResponse(c, 400, err.Error(), nil)
+		} else if errors.Is(err, utils.ErrUserNotFound) {
+			utils.SendResponse(c, 404, err.Error(), nil)
+		} else {
+			utils.SendResponse(c, 500, "设置配额失败："+err.Error(), nil)
+		}
+		return
+	}
+
+	// 3. 返回响应
+	utils.SendResponse(c, 200, "设置成功", quota)
+}
+
+// DeleteUserQuota 删除指定用户的单独配额，恢复使用角色默认配额（管理员）
+func DeleteUserQuota(c *gin.Context) {
+	// 1. 解析路径参数（用户ID）
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.SendResponse(c, 400, "无效的用户ID", nil)
+		return
+	}
+
+	// 2. 调用服务层删除
+	if err := services.DeleteUserQuota(c.Request.Context(), userID); err != nil {
+		utils.SendResponse(c, 500, "删除配额失败："+err.Error(), nil)
+		return
+	}
+
+	// 3. 返回响应
+	utils.SendResponse(c, 200, "已恢复默认配额", nil)
+}
@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"CodeQuizAI/config"
+	"CodeQuizAI/services"
+	"CodeQuizAI/utils"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// UploadSource 上传出题资料：multipart/form-data 上传文件（file 字段，name 字段可指定名称，默认使用文件名），
+// 或以 JSON 提交资料名称和内容
+func UploadSource(c *gin.Context) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+
+	// 1. 解析请求参数（限制请求体大小，JSON 转义后的内容最多约为原文的6倍）
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(cfg.SourceMaxBytes)*6+1<<20)
+	var req services.CreateSourceRequest
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			utils.SendResponse(c, 400, "参数错误：缺少上传文件 file", nil)
+			return
+		}
+		if file.Size > int64(cfg.SourceMaxBytes) {
+			utils.SendResponse(c, 413, fmt.Sprintf("%s（%d 字节，上限 %d 字节）", utils.ErrSourceTooLarge, file.Size, cfg.SourceMaxBytes), nil)
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			utils.SendResponse(c, 400, "读取上传文件失败："+err.Error(), nil)
+			return
+		}
+		defer f.Close()
+		content, err := io.ReadAll(f)
+		if err != nil {
+			utils.SendResponse(c, 400, "读取上传文件失败："+err.Error(), nil)
+			return
+		}
+		req.Name = c.DefaultPostForm("name", file.Filename)
+		req.Content = string(content)
+		if err := binding.Validator.ValidateStruct(&req); err != nil {
+			utils.SendResponse(c, 400, "参数错误："+err.Error(), nil)
+			return
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendResponse(c, 400, "参数错误："+err.Error(), nil)
+		return
+	}
+
+	// 2. 保存资料
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+	source, err := services.CreateSourceDocument(c.Request.Context(), userIDInt64, req, cfg)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrSourceTooLarge):
+			utils.SendResponse(c, 413, err.Error(), nil)
+		case errors.Is(err, utils.ErrSourceNotText), errors.Is(err, utils.ErrSourceEmpty):
+			utils.SendResponse(c, 400, err.Error(), nil)
+		default:
+			utils.SendResponse(c, 500, err.Error(), nil)
+		}
+		return
+	}
+	utils.SendResponse(c, 201, "资料上传成功", source)
+}
+
+// RegisterRepositorySource 登记服务器上的本地仓库作为出题资料（仅管理员，仓库须位于 SOURCE_REPO_ROOTS 中的目录下）
+func RegisterRepositorySource(c *gin.Context) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+	var req services.CreateRepositorySourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendResponse(c, 400, "参数错误："+err.Error(), nil)
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+
+	source, err := services.CreateRepositorySource(c.Request.Context(), userIDInt64, req, cfg)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrRepositoryDisabled), errors.Is(err, utils.ErrRepositoryNotAllowed):
+			utils.SendResponse(c, 403, err.Error(), nil)
+		case errors.Is(err, utils.ErrRepositoryTooLarge):
+			utils.SendResponse(c, 413, err.Error(), nil)
+		case errors.Is(err, utils.ErrSourceEmpty):
+			utils.SendResponse(c, 400, err.Error(), nil)
+		default:
+			utils.SendResponse(c, 500, err.Error(), nil)
+		}
+		return
+	}
+	utils.SendResponse(c, 201, "仓库登记成功", source)
+}
+
+// ListSources 查询当前用户上传的出题资料（不含内容）
+func ListSources(c *gin.Context) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("配置加载失败: %v", err)
+	}
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+
+	sources, err := services.ListSourceDocuments(c.Request.Context(), userIDInt64, cfg)
+	if err != nil {
+		utils.SendResponse(c, 500, err.Error(), nil)
+		return
+	}
+	utils.SendResponse(c, 200, "查询成功", gin.H{
+		"list":  sources,
+		"total": len(sources),
+	})
+}
+
+// GetSource 查询出题资料的内容
+func GetSource(c *gin.Context) {
+	sourceID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.SendResponse(c, 400, "无效的资料ID", nil)
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+
+	source, err := services.GetSourceDocument(c.Request.Context(), sourceID, userIDInt64)
+	if err != nil {
+		if errors.Is(err, utils.ErrSourceNotFound) {
+			utils.SendResponse(c, 404, err.Error(), nil)
+		} else {
+			utils.SendResponse(c, 500, err.Error(), nil)
+		}
+		return
+	}
+	utils.SendResponse(c, 200, "查询成功", source)
+}
+
+// DeleteSource 删除出题资料（已生成的题目保留出处）
+func DeleteSource(c *gin.Context) {
+	sourceID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.SendResponse(c, 400, "无效的资料ID", nil)
+		return
+	}
+	userID, _ := c.Get("user_id")
+	userIDInt64, _ := userID.(int64)
+
+	if err := services.DeleteSourceDocument(c.Request.Context(), sourceID, userIDInt64); err != nil {
+		if errors.Is(err, utils.ErrSourceNotFound) {
+			utils.SendResponse(c, 404, err.Error(), nil)
+		} else {
+			utils.SendResponse(c, 500, err.Error(), nil)
+		}
+		return
+	}
+	utils.SendResponse(c, 200, "资料已删除", nil)
+}
+
+// checkSource 按资料出题时检查资料是否存在且属于当前用户，不存在时返回 404 并返回 false
+func checkSource(c *gin.Context, userID, sourceID int64) bool {
+	if sourceID == 0 {
+		return true
+	}
+	if _, err := services.GetSourceDocument(c.Request.Context(), sourceID, userID); err != nil {
+		if errors.Is(err, utils.ErrSourceNotFound) {
+			utils.SendResponse(c, 404, err.Error(), nil)
+		} else {
+			utils.SendResponse(c, 500, err.Error(), nil)
+		}
+		return false
+	}
+	return true
+}
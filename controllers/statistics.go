@@ -26,7 +26,7 @@ func GetUserStatistics(c *gin.Context) {
 	}
 
 	// 3. 权限验证：仅允许管理员或用户本人查看统计信息
-	currentUserIDInt64 := int64(currentUserID.(uint))
+	currentUserIDInt64, _ := currentUserID.(int64)
 	if currentUserIDInt64 != targetUserID {
 		// 检查当前用户是否为管理员
 		user, err := dao.Q.User.WithContext(c).Where(dao.User.ID.Eq(currentUserIDInt64)).First()
@@ -16,54 +16,104 @@ import (
 )
 
 var (
-	Q             = new(Query)
-	Paper         *paper
-	PaperQuestion *paperQuestion
-	Question      *question
-	TempQuestion  *tempQuestion
-	User          *user
+	Q                    = new(Query)
+	AICall               *aICall
+	AIResponseCache      *aIResponseCache
+	GenerationJob        *generationJob
+	IdempotencyKey       *idempotencyKey
+	JanitorRun           *janitorRun
+	Paper                *paper
+	PaperQuestion        *paperQuestion
+	PromptTemplate       *promptTemplate
+	Question             *question
+	QuotaReservation     *quotaReservation
+	SourceDocument       *sourceDocument
+	TempQuestion         *tempQuestion
+	TempQuestionRevision *tempQuestionRevision
+	User                 *user
+	UserQuota            *userQuota
 )
 
 func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	*Q = *Use(db, opts...)
+	AICall = &Q.AICall
+	AIResponseCache = &Q.AIResponseCache
+	GenerationJob = &Q.GenerationJob
+	IdempotencyKey = &Q.IdempotencyKey
+	JanitorRun = &Q.JanitorRun
 	Paper = &Q.Paper
 	PaperQuestion = &Q.PaperQuestion
+	PromptTemplate = &Q.PromptTemplate
 	Question = &Q.Question
+	QuotaReservation = &Q.QuotaReservation
+	SourceDocument = &Q.SourceDocument
 	TempQuestion = &Q.TempQuestion
+	TempQuestionRevision = &Q.TempQuestionRevision
 	User = &Q.User
+	UserQuota = &Q.UserQuota
 }
 
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
-		db:            db,
-		Paper:         newPaper(db, opts...),
-		PaperQuestion: newPaperQuestion(db, opts...),
-		Question:      newQuestion(db, opts...),
-		TempQuestion:  newTempQuestion(db, opts...),
-		User:          newUser(db, opts...),
+		db:                   db,
+		AICall:               newAICall(db, opts...),
+		AIResponseCache:      newAIResponseCache(db, opts...),
+		GenerationJob:        newGenerationJob(db, opts...),
+		IdempotencyKey:       newIdempotencyKey(db, opts...),
+		JanitorRun:           newJanitorRun(db, opts...),
+		Paper:                newPaper(db, opts...),
+		PaperQuestion:        newPaperQuestion(db, opts...),
+		PromptTemplate:       newPromptTemplate(db, opts...),
+		Question:             newQuestion(db, opts...),
+		QuotaReservation:     newQuotaReservation(db, opts...),
+		SourceDocument:       newSourceDocument(db, opts...),
+		TempQuestion:         newTempQuestion(db, opts...),
+		TempQuestionRevision: newTempQuestionRevision(db, opts...),
+		User:                 newUser(db, opts...),
+		UserQuota:            newUserQuota(db, opts...),
 	}
 }
 
 type Query struct {
 	db *gorm.DB
 
-	Paper         paper
-	PaperQuestion paperQuestion
-	Question      question
-	TempQuestion  tempQuestion
-	User          user
+	AICall               aICall
+	AIResponseCache      aIResponseCache
+	GenerationJob        generationJob
+	IdempotencyKey       idempotencyKey
+	JanitorRun           janitorRun
+	Paper                paper
+	PaperQuestion        paperQuestion
+	PromptTemplate       promptTemplate
+	Question             question
+	QuotaReservation     quotaReservation
+	SourceDocument       sourceDocument
+	TempQuestion         tempQuestion
+	TempQuestionRevision tempQuestionRevision
+	User                 user
+	UserQuota            userQuota
 }
 
 func (q *Query) Available() bool { return q.db != nil }
 
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
-		db:            db,
-		Paper:         q.Paper.clone(db),
-		PaperQuestion: q.PaperQuestion.clone(db),
-		Question:      q.Question.clone(db),
-		TempQuestion:  q.TempQuestion.clone(db),
-		User:          q.User.clone(db),
+		db:                   db,
+		AICall:               q.AICall.clone(db),
+		AIResponseCache:      q.AIResponseCache.clone(db),
+		GenerationJob:        q.GenerationJob.clone(db),
+		IdempotencyKey:       q.IdempotencyKey.clone(db),
+		JanitorRun:           q.JanitorRun.clone(db),
+		Paper:                q.Paper.clone(db),
+		PaperQuestion:        q.PaperQuestion.clone(db),
+		PromptTemplate:       q.PromptTemplate.clone(db),
+		Question:             q.Question.clone(db),
+		QuotaReservation:     q.QuotaReservation.clone(db),
+		SourceDocument:       q.SourceDocument.clone(db),
+		TempQuestion:         q.TempQuestion.clone(db),
+		TempQuestionRevision: q.TempQuestionRevision.clone(db),
+		User:                 q.User.clone(db),
+		UserQuota:            q.UserQuota.clone(db),
 	}
 }
 
@@ -77,30 +127,60 @@ func (q *Query) WriteDB() *Query {
 
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
-		db:            db,
-		Paper:         q.Paper.replaceDB(db),
-		PaperQuestion: q.PaperQuestion.replaceDB(db),
-		Question:      q.Question.replaceDB(db),
-		TempQuestion:  q.TempQuestion.replaceDB(db),
-		User:          q.User.replaceDB(db),
+		db:                   db,
+		AICall:               q.AICall.replaceDB(db),
+		AIResponseCache:      q.AIResponseCache.replaceDB(db),
+		GenerationJob:        q.GenerationJob.replaceDB(db),
+		IdempotencyKey:       q.IdempotencyKey.replaceDB(db),
+		JanitorRun:           q.JanitorRun.replaceDB(db),
+		Paper:                q.Paper.replaceDB(db),
+		PaperQuestion:        q.PaperQuestion.replaceDB(db),
+		PromptTemplate:       q.PromptTemplate.replaceDB(db),
+		Question:             q.Question.replaceDB(db),
+		QuotaReservation:     q.QuotaReservation.replaceDB(db),
+		SourceDocument:       q.SourceDocument.replaceDB(db),
+		TempQuestion:         q.TempQuestion.replaceDB(db),
+		TempQuestionRevision: q.TempQuestionRevision.replaceDB(db),
+		User:                 q.User.replaceDB(db),
+		UserQuota:            q.UserQuota.replaceDB(db),
 	}
 }
 
 type queryCtx struct {
-	Paper         IPaperDo
-	PaperQuestion IPaperQuestionDo
-	Question      IQuestionDo
-	TempQuestion  ITempQuestionDo
-	User          IUserDo
+	AICall               IAICallDo
+	AIResponseCache      IAIResponseCacheDo
+	GenerationJob        IGenerationJobDo
+	IdempotencyKey       IIdempotencyKeyDo
+	JanitorRun           IJanitorRunDo
+	Paper                IPaperDo
+	PaperQuestion        IPaperQuestionDo
+	PromptTemplate       IPromptTemplateDo
+	Question             IQuestionDo
+	QuotaReservation     IQuotaReservationDo
+	SourceDocument       ISourceDocumentDo
+	TempQuestion         ITempQuestionDo
+	TempQuestionRevision ITempQuestionRevisionDo
+	User                 IUserDo
+	UserQuota            IUserQuotaDo
 }
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
-		Paper:         q.Paper.WithContext(ctx),
-		PaperQuestion: q.PaperQuestion.WithContext(ctx),
-		Question:      q.Question.WithContext(ctx),
-		TempQuestion:  q.TempQuestion.WithContext(ctx),
-		User:          q.User.WithContext(ctx),
+		AICall:               q.AICall.WithContext(ctx),
+		AIResponseCache:      q.AIResponseCache.WithContext(ctx),
+		GenerationJob:        q.GenerationJob.WithContext(ctx),
+		IdempotencyKey:       q.IdempotencyKey.WithContext(ctx),
+		JanitorRun:           q.JanitorRun.WithContext(ctx),
+		Paper:                q.Paper.WithContext(ctx),
+		PaperQuestion:        q.PaperQuestion.WithContext(ctx),
+		PromptTemplate:       q.PromptTemplate.WithContext(ctx),
+		Question:             q.Question.WithContext(ctx),
+		QuotaReservation:     q.QuotaReservation.WithContext(ctx),
+		SourceDocument:       q.SourceDocument.WithContext(ctx),
+		TempQuestion:         q.TempQuestion.WithContext(ctx),
+		TempQuestionRevision: q.TempQuestionRevision.WithContext(ctx),
+		User:                 q.User.WithContext(ctx),
+		UserQuota:            q.UserQuota.WithContext(ctx),
 	}
 }
 
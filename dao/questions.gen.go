@@ -33,10 +33,18 @@ func newQuestion(db *gorm.DB, opts ...gen.DOOption) question {
 	_question.QuestionType = field.NewString(tableName, "question_type")
 	_question.Options = field.NewString(tableName, "options")
 	_question.Answer = field.NewString(tableName, "answer")
+	_question.CodeSnippet = field.NewString(tableName, "code_snippet")
+	_question.CodeLanguage = field.NewString(tableName, "code_language")
 	_question.Explanation = field.NewString(tableName, "explanation")
 	_question.Keywords = field.NewString(tableName, "keywords")
 	_question.Language = field.NewString(tableName, "language")
 	_question.AiModel = field.NewString(tableName, "ai_model")
+	_question.Difficulty = field.NewString(tableName, "difficulty")
+	_question.VerificationStatus = field.NewString(tableName, "verification_status")
+	_question.SourceID = field.NewInt64(tableName, "source_id")
+	_question.SourcePath = field.NewString(tableName, "source_path")
+	_question.SourceStartLine = field.NewInt(tableName, "source_start_line")
+	_question.SourceEndLine = field.NewInt(tableName, "source_end_line")
 	_question.UserID = field.NewInt64(tableName, "user_id")
 	_question.CreatedAt = field.NewTime(tableName, "created_at")
 	_question.UpdatedAt = field.NewTime(tableName, "updated_at")
@@ -55,21 +63,29 @@ func newQuestion(db *gorm.DB, opts ...gen.DOOption) question {
 type question struct {
 	questionDo questionDo
 
-	ALL          field.Asterisk
-	ID           field.Int64
-	Title        field.String
-	QuestionType field.String
-	Options      field.String
-	Answer       field.String
-	Explanation  field.String
-	Keywords     field.String
-	Language     field.String
-	AiModel      field.String
-	UserID       field.Int64
-	CreatedAt    field.Time
-	UpdatedAt    field.Time
-	DeletedAt    field.Field
-	User         questionBelongsToUser
+	ALL                field.Asterisk
+	ID                 field.Int64
+	Title              field.String
+	QuestionType       field.String
+	Options            field.String
+	Answer             field.String
+	CodeSnippet        field.String
+	CodeLanguage       field.String
+	Explanation        field.String
+	Keywords           field.String
+	Language           field.String
+	AiModel            field.String
+	Difficulty         field.String
+	VerificationStatus field.String
+	SourceID           field.Int64
+	SourcePath         field.String
+	SourceStartLine    field.Int
+	SourceEndLine      field.Int
+	UserID             field.Int64
+	CreatedAt          field.Time
+	UpdatedAt          field.Time
+	DeletedAt          field.Field
+	User               questionBelongsToUser
 
 	fieldMap map[string]field.Expr
 }
@@ -91,10 +107,18 @@ func (q *question) updateTableName(table string) *question {
 	q.QuestionType = field.NewString(table, "question_type")
 	q.Options = field.NewString(table, "options")
 	q.Answer = field.NewString(table, "answer")
+	q.CodeSnippet = field.NewString(table, "code_snippet")
+	q.CodeLanguage = field.NewString(table, "code_language")
 	q.Explanation = field.NewString(table, "explanation")
 	q.Keywords = field.NewString(table, "keywords")
 	q.Language = field.NewString(table, "language")
 	q.AiModel = field.NewString(table, "ai_model")
+	q.Difficulty = field.NewString(table, "difficulty")
+	q.VerificationStatus = field.NewString(table, "verification_status")
+	q.SourceID = field.NewInt64(table, "source_id")
+	q.SourcePath = field.NewString(table, "source_path")
+	q.SourceStartLine = field.NewInt(table, "source_start_line")
+	q.SourceEndLine = field.NewInt(table, "source_end_line")
 	q.UserID = field.NewInt64(table, "user_id")
 	q.CreatedAt = field.NewTime(table, "created_at")
 	q.UpdatedAt = field.NewTime(table, "updated_at")
@@ -123,16 +147,24 @@ func (q *question) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (q *question) fillFieldMap() {
-	q.fieldMap = make(map[string]field.Expr, 14)
+	q.fieldMap = make(map[string]field.Expr, 22)
 	q.fieldMap["id"] = q.ID
 	q.fieldMap["title"] = q.Title
 	q.fieldMap["question_type"] = q.QuestionType
 	q.fieldMap["options"] = q.Options
 	q.fieldMap["answer"] = q.Answer
+	q.fieldMap["code_snippet"] = q.CodeSnippet
+	q.fieldMap["code_language"] = q.CodeLanguage
 	q.fieldMap["explanation"] = q.Explanation
 	q.fieldMap["keywords"] = q.Keywords
 	q.fieldMap["language"] = q.Language
 	q.fieldMap["ai_model"] = q.AiModel
+	q.fieldMap["difficulty"] = q.Difficulty
+	q.fieldMap["verification_status"] = q.VerificationStatus
+	q.fieldMap["source_id"] = q.SourceID
+	q.fieldMap["source_path"] = q.SourcePath
+	q.fieldMap["source_start_line"] = q.SourceStartLine
+	q.fieldMap["source_end_line"] = q.SourceEndLine
 	q.fieldMap["user_id"] = q.UserID
 	q.fieldMap["created_at"] = q.CreatedAt
 	q.fieldMap["updated_at"] = q.UpdatedAt
@@ -35,12 +35,30 @@ func newTempQuestion(db *gorm.DB, opts ...gen.DOOption) tempQuestion {
 	_tempQuestion.QuestionType = field.NewString(tableName, "question_type")
 	_tempQuestion.Options = field.NewString(tableName, "options")
 	_tempQuestion.Answer = field.NewString(tableName, "answer")
+	_tempQuestion.CodeSnippet = field.NewString(tableName, "code_snippet")
+	_tempQuestion.CodeLanguage = field.NewString(tableName, "code_language")
 	_tempQuestion.Explanation = field.NewString(tableName, "explanation")
 	_tempQuestion.Keywords = field.NewString(tableName, "keywords")
+	_tempQuestion.GenerateParams = field.NewString(tableName, "generate_params")
 	_tempQuestion.Language = field.NewString(tableName, "language")
 	_tempQuestion.AiModel = field.NewString(tableName, "ai_model")
+	_tempQuestion.Difficulty = field.NewString(tableName, "difficulty")
+	_tempQuestion.SimilarQuestionID = field.NewInt64(tableName, "similar_question_id")
+	_tempQuestion.Similarity = field.NewFloat64(tableName, "similarity")
+	_tempQuestion.TemplateVersion = field.NewString(tableName, "template_version")
+	_tempQuestion.VerificationStatus = field.NewString(tableName, "verification_status")
+	_tempQuestion.ReviewerModel = field.NewString(tableName, "reviewer_model")
+	_tempQuestion.ReviewerAnswer = field.NewString(tableName, "reviewer_answer")
+	_tempQuestion.ReviewerReasoning = field.NewString(tableName, "reviewer_reasoning")
+	_tempQuestion.SourceID = field.NewInt64(tableName, "source_id")
+	_tempQuestion.SourcePath = field.NewString(tableName, "source_path")
+	_tempQuestion.SourceStartLine = field.NewInt(tableName, "source_start_line")
+	_tempQuestion.SourceEndLine = field.NewInt(tableName, "source_end_line")
+	_tempQuestion.Revision = field.NewInt(tableName, "revision")
+	_tempQuestion.QuestionID = field.NewInt64(tableName, "question_id")
 	_tempQuestion.UserID = field.NewInt64(tableName, "user_id")
 	_tempQuestion.CreatedAt = field.NewTime(tableName, "created_at")
+	_tempQuestion.ExpiredAt = field.NewTime(tableName, "expired_at")
 	_tempQuestion.DeletedAt = field.NewField(tableName, "deleted_at")
 
 	_tempQuestion.fillFieldMap()
@@ -51,21 +69,39 @@ func newTempQuestion(db *gorm.DB, opts ...gen.DOOption) tempQuestion {
 type tempQuestion struct {
 	tempQuestionDo tempQuestionDo
 
-	ALL          field.Asterisk
-	ID           field.Int64
-	PreviewID    field.String
-	TempID       field.String
-	Title        field.String
-	QuestionType field.String
-	Options      field.String
-	Answer       field.String
-	Explanation  field.String
-	Keywords     field.String
-	Language     field.String
-	AiModel      field.String
-	UserID       field.Int64
-	CreatedAt    field.Time
-	DeletedAt    field.Field
+	ALL                field.Asterisk
+	ID                 field.Int64
+	PreviewID          field.String
+	TempID             field.String
+	Title              field.String
+	QuestionType       field.String
+	Options            field.String
+	Answer             field.String
+	CodeSnippet        field.String
+	CodeLanguage       field.String
+	Explanation        field.String
+	Keywords           field.String
+	GenerateParams     field.String
+	Language           field.String
+	AiModel            field.String
+	Difficulty         field.String
+	SimilarQuestionID  field.Int64
+	Similarity         field.Float64
+	TemplateVersion    field.String
+	VerificationStatus field.String
+	ReviewerModel      field.String
+	ReviewerAnswer     field.String
+	ReviewerReasoning  field.String
+	SourceID           field.Int64
+	SourcePath         field.String
+	SourceStartLine    field.Int
+	SourceEndLine      field.Int
+	Revision           field.Int
+	QuestionID         field.Int64
+	UserID             field.Int64
+	CreatedAt          field.Time
+	ExpiredAt          field.Time
+	DeletedAt          field.Field
 
 	fieldMap map[string]field.Expr
 }
@@ -89,12 +125,30 @@ func (t *tempQuestion) updateTableName(table string) *tempQuestion {
 	t.QuestionType = field.NewString(table, "question_type")
 	t.Options = field.NewString(table, "options")
 	t.Answer = field.NewString(table, "answer")
+	t.CodeSnippet = field.NewString(table, "code_snippet")
+	t.CodeLanguage = field.NewString(table, "code_language")
 	t.Explanation = field.NewString(table, "explanation")
 	t.Keywords = field.NewString(table, "keywords")
+	t.GenerateParams = field.NewString(table, "generate_params")
 	t.Language = field.NewString(table, "language")
 	t.AiModel = field.NewString(table, "ai_model")
+	t.Difficulty = field.NewString(table, "difficulty")
+	t.SimilarQuestionID = field.NewInt64(table, "similar_question_id")
+	t.Similarity = field.NewFloat64(table, "similarity")
+	t.TemplateVersion = field.NewString(table, "template_version")
+	t.VerificationStatus = field.NewString(table, "verification_status")
+	t.ReviewerModel = field.NewString(table, "reviewer_model")
+	t.ReviewerAnswer = field.NewString(table, "reviewer_answer")
+	t.ReviewerReasoning = field.NewString(table, "reviewer_reasoning")
+	t.SourceID = field.NewInt64(table, "source_id")
+	t.SourcePath = field.NewString(table, "source_path")
+	t.SourceStartLine = field.NewInt(table, "source_start_line")
+	t.SourceEndLine = field.NewInt(table, "source_end_line")
+	t.Revision = field.NewInt(table, "revision")
+	t.QuestionID = field.NewInt64(table, "question_id")
 	t.UserID = field.NewInt64(table, "user_id")
 	t.CreatedAt = field.NewTime(table, "created_at")
+	t.ExpiredAt = field.NewTime(table, "expired_at")
 	t.DeletedAt = field.NewField(table, "deleted_at")
 
 	t.fillFieldMap()
@@ -124,7 +178,7 @@ func (t *tempQuestion) GetFieldByName(fieldName string) (field.OrderExpr, bool)
 }
 
 func (t *tempQuestion) fillFieldMap() {
-	t.fieldMap = make(map[string]field.Expr, 14)
+	t.fieldMap = make(map[string]field.Expr, 32)
 	t.fieldMap["id"] = t.ID
 	t.fieldMap["preview_id"] = t.PreviewID
 	t.fieldMap["temp_id"] = t.TempID
@@ -132,12 +186,30 @@ func (t *tempQuestion) fillFieldMap() {
 	t.fieldMap["question_type"] = t.QuestionType
 	t.fieldMap["options"] = t.Options
 	t.fieldMap["answer"] = t.Answer
+	t.fieldMap["code_snippet"] = t.CodeSnippet
+	t.fieldMap["code_language"] = t.CodeLanguage
 	t.fieldMap["explanation"] = t.Explanation
 	t.fieldMap["keywords"] = t.Keywords
+	t.fieldMap["generate_params"] = t.GenerateParams
 	t.fieldMap["language"] = t.Language
 	t.fieldMap["ai_model"] = t.AiModel
+	t.fieldMap["difficulty"] = t.Difficulty
+	t.fieldMap["similar_question_id"] = t.SimilarQuestionID
+	t.fieldMap["similarity"] = t.Similarity
+	t.fieldMap["template_version"] = t.TemplateVersion
+	t.fieldMap["verification_status"] = t.VerificationStatus
+	t.fieldMap["reviewer_model"] = t.ReviewerModel
+	t.fieldMap["reviewer_answer"] = t.ReviewerAnswer
+	t.fieldMap["reviewer_reasoning"] = t.ReviewerReasoning
+	t.fieldMap["source_id"] = t.SourceID
+	t.fieldMap["source_path"] = t.SourcePath
+	t.fieldMap["source_start_line"] = t.SourceStartLine
+	t.fieldMap["source_end_line"] = t.SourceEndLine
+	t.fieldMap["revision"] = t.Revision
+	t.fieldMap["question_id"] = t.QuestionID
 	t.fieldMap["user_id"] = t.UserID
 	t.fieldMap["created_at"] = t.CreatedAt
+	t.fieldMap["expired_at"] = t.ExpiredAt
 	t.fieldMap["deleted_at"] = t.DeletedAt
 }
 
@@ -31,6 +31,16 @@ func main() {
 		models.Paper{},
 		models.PaperQuestion{},
 		models.TempQuestion{},
+		models.GenerationJob{},
+		models.PromptTemplate{},
+		models.AICall{},
+		models.UserQuota{},
+		models.AIResponseCache{},
+		models.TempQuestionRevision{},
+		models.JanitorRun{},
+		models.IdempotencyKey{},
+		models.SourceDocument{},
+		models.QuotaReservation{},
 	)
 
 	// 执行生成
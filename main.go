@@ -1,16 +1,21 @@
 package main
 
 import (
+	"CodeQuizAI/ai"
 	"CodeQuizAI/config"
 	"CodeQuizAI/dao"
 	"CodeQuizAI/middlewares"
 	"CodeQuizAI/router"
+	"CodeQuizAI/services"
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -25,12 +30,19 @@ func main() {
 		log.Fatalf("配置加载失败: %v", err)
 	}
 
+	// 启用已配置的AI模型
+	if err := ai.Setup(cfg); err != nil {
+		log.Fatalf("AI模型初始化失败: %v", err)
+	}
+
 	// 设置 Gin 运行模式（从配置中读取）
 	gin.SetMode(cfg.GinMode)
 
 	// 配置参数
 	dbPath := cfg.DBPath
 	initScriptPath := "./migrations/init.sql"
+	migrationsDir := "./migrations"
+	promptsDir := "./prompts"
 
 	// 初始化数据库
 	err = initDatabase(dbPath, initScriptPath)
@@ -38,14 +50,32 @@ func main() {
 		log.Fatalf("数据库初始化失败: %v", err)
 	}
 
+	// 执行增量迁移
+	if err := runMigrations(dbPath, migrationsDir); err != nil {
+		log.Fatalf("数据库迁移失败: %v", err)
+	}
+
 	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
 	if err != nil {
-		log.Fatalf("连接数据库失败: %w", err)
+		log.Fatalf("连接数据库失败: %v", err)
 	}
 
 	// 设置DAO默认数据库连接
 	dao.SetDefault(db)
 
+	// 导入提示语模板文件
+	if err := services.SeedPromptTemplates(context.Background(), promptsDir); err != nil {
+		log.Fatalf("导入提示语模板失败: %v", err)
+	}
+
+	// 启动异步生成任务的工作协程
+	if err := services.StartJobWorkers(cfg); err != nil {
+		log.Fatalf("启动生成任务失败: %v", err)
+	}
+
+	// 启动过期预览的清理任务
+	services.StartPreviewJanitor(cfg)
+
 	// 初始化JWT配置
 	middlewares.InitJWT(cfg.JWTSecret, time.Duration(cfg.JWTExpireHours)*time.Hour) // 密钥和过期时间
 
@@ -97,7 +127,7 @@ func initDatabase(dbPath, initScriptPath string) error {
 }
 
 // 执行SQL脚本文件
-func executeSQLScript(db *sql.DB, scriptPath string) error {
+func executeSQLScript(db *sql.DB, scriptPath string) (err error) {
 	// 读取SQL脚本
 	script, err := os.ReadFile(scriptPath)
 	if err != nil {
@@ -120,8 +150,12 @@ func executeSQLScript(db *sql.DB, scriptPath string) error {
 		}
 	}()
 
-	// 按分号分割SQL语句并执行
-	for _, stmt := range strings.Split(string(script), ";") {
+	return execStatements(tx, string(script))
+}
+
+// 按分号分割SQL语句并在事务中逐条执行
+func execStatements(tx *sql.Tx, script string) error {
+	for _, stmt := range strings.Split(script, ";") {
 		stmt = strings.TrimSpace(stmt)
 		if stmt == "" {
 			continue
@@ -135,3 +169,78 @@ func executeSQLScript(db *sql.DB, scriptPath string) error {
 
 	return nil
 }
+
+// 迁移脚本文件名格式：三位序号_描述.sql（如 001_create_generation_jobs.sql）
+var migrationFilePattern = regexp.MustCompile(`^(\d{3})_[a-z0-9_]+\.sql$`)
+
+// 执行增量迁移脚本（已执行过的版本记录在 schema_migrations 表中，不会重复执行）
+func runMigrations(dbPath, migrationsDir string) (err error) {
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		return fmt.Errorf("连接数据库失败: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("获取SQL数据库实例失败: %w", err)
+	}
+	defer sqlDB.Close()
+
+	// 1. 创建迁移记录表
+	if _, err := sqlDB.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version VARCHAR(16) PRIMARY KEY,
+		applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return fmt.Errorf("创建迁移记录表失败: %w", err)
+	}
+
+	// 2. 按序号排序迁移脚本
+	entries, err := os.ReadDir(migrationsDir)
+	if err != nil {
+		return fmt.Errorf("读取迁移目录失败: %w", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && migrationFilePattern.MatchString(entry.Name()) {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+
+	// 3. 逐个执行未执行过的脚本（脚本与版本记录在同一事务中提交）
+	for _, name := range files {
+		version := migrationFilePattern.FindStringSubmatch(name)[1]
+
+		var count int
+		if err := sqlDB.QueryRow("SELECT COUNT(*) FROM schema_migrations WHERE version = ?", version).Scan(&count); err != nil {
+			return fmt.Errorf("查询迁移记录失败: %w", err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		script, err := os.ReadFile(filepath.Join(migrationsDir, name))
+		if err != nil {
+			return fmt.Errorf("读取迁移脚本失败: %w", err)
+		}
+
+		tx, err := sqlDB.Begin()
+		if err != nil {
+			return fmt.Errorf("开始事务失败: %w", err)
+		}
+		if err := execStatements(tx, string(script)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("执行迁移脚本 %s 失败: %w", name, err)
+		}
+		if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (?)", version); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("记录迁移版本失败: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("提交迁移事务失败: %w", err)
+		}
+
+		log.Printf("已执行迁移脚本: %s", name)
+	}
+
+	return nil
+}
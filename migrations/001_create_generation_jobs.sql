@@ -0,0 +1,20 @@
+-- 创建异步生成任务表（记录题目生成任务的状态，服务重启后可恢复）
+CREATE TABLE IF NOT EXISTS generation_jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,            -- 提交任务的用户ID
+    status VARCHAR(20) NOT NULL,         -- 任务状态（queued/running/succeeded/failed）
+    request TEXT NOT NULL,               -- 生成请求参数（JSON格式）
+    preview_id VARCHAR(64) NOT NULL,     -- 预览批次ID（提交时分配）
+    ai_model VARCHAR(50),                -- 实际生成题目的模型
+    fallback_used BOOLEAN DEFAULT 0,     -- 是否发生了模型降级
+    attempts TEXT,                       -- 各模型的尝试记录（JSON格式）
+    result TEXT,                         -- 生成结果（JSON格式，不含题目）
+    error TEXT,                          -- 失败原因
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    started_at DATETIME NULL,
+    finished_at DATETIME NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_generation_jobs_status ON generation_jobs(status)
@@ -0,0 +1,20 @@
+-- 创建提示语模板表（同名模板按版本号区分，每个名称同一时间只有一个激活版本）
+CREATE TABLE IF NOT EXISTS prompt_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) NOT NULL,              -- 模板名称
+    version INTEGER NOT NULL,                -- 版本号（同名模板内递增）
+    language VARCHAR(50) DEFAULT '',         -- 适用的编程语言（空表示不限）
+    question_type VARCHAR(20) DEFAULT '',    -- 适用的题型（空表示不限）
+    ai_model VARCHAR(50) DEFAULT '',         -- 适用的AI模型（空表示不限）
+    content TEXT NOT NULL,                   -- 模板内容（text/template 语法）
+    active BOOLEAN DEFAULT 0,                -- 是否为该名称的激活版本
+    source VARCHAR(20) NOT NULL,             -- 来源（file/api）
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (name, version)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_prompt_templates_active ON prompt_templates(active);
+
+-- 临时题目记录生成时使用的模板版本（如 default@1）
+ALTER TABLE temp_questions ADD COLUMN template_version VARCHAR(100) DEFAULT ''
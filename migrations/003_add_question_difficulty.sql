@@ -0,0 +1,6 @@
+-- 题目难度（easy/medium/hard，空表示未指定）
+ALTER TABLE temp_questions ADD COLUMN difficulty VARCHAR(10) DEFAULT '';
+
+ALTER TABLE questions ADD COLUMN difficulty VARCHAR(10) DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_questions_difficulty ON questions(difficulty)
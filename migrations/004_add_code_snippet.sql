@@ -0,0 +1,8 @@
+-- 题目附带的代码片段及其语言标记（空表示没有代码）
+ALTER TABLE temp_questions ADD COLUMN code_snippet TEXT DEFAULT '';
+
+ALTER TABLE temp_questions ADD COLUMN code_language VARCHAR(50) DEFAULT '';
+
+ALTER TABLE questions ADD COLUMN code_snippet TEXT DEFAULT '';
+
+ALTER TABLE questions ADD COLUMN code_language VARCHAR(50) DEFAULT ''
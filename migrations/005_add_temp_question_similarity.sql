@@ -0,0 +1,4 @@
+-- 临时题目与题库中最相似题目的比对结果（近似重复检测）
+ALTER TABLE temp_questions ADD COLUMN similar_question_id INTEGER;
+
+ALTER TABLE temp_questions ADD COLUMN similarity REAL DEFAULT 0
@@ -0,0 +1,19 @@
+-- 创建AI调用记录表（每次调用的 token 用量、耗时、状态和估算费用）
+CREATE TABLE IF NOT EXISTS ai_calls (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,            -- 发起调用的用户ID
+    ai_model VARCHAR(50) NOT NULL,       -- 调用的模型
+    purpose VARCHAR(20) NOT NULL,        -- 调用用途（generate/stream/reprompt）
+    preview_id VARCHAR(64) DEFAULT '',   -- 关联的预览批次ID
+    prompt_tokens INTEGER DEFAULT 0,     -- 提示语 token 数
+    completion_tokens INTEGER DEFAULT 0, -- 生成内容 token 数
+    latency_ms INTEGER DEFAULT 0,        -- 调用耗时（毫秒，含重试）
+    status VARCHAR(20) NOT NULL,         -- 调用状态（success/error）
+    error TEXT,                          -- 失败原因
+    cost REAL DEFAULT 0,                 -- 按配置单价估算的费用
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_ai_calls_user_id ON ai_calls(user_id);
+CREATE INDEX IF NOT EXISTS idx_ai_calls_created_at ON ai_calls(created_at)
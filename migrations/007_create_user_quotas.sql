@@ -0,0 +1,14 @@
+-- 创建用户配额表（单个用户的生成配额，覆盖角色默认配额；字段为空时使用角色默认值，0 表示不限）
+CREATE TABLE IF NOT EXISTS user_quotas (
+    user_id INTEGER PRIMARY KEY,         -- 用户ID
+    daily_questions INTEGER NULL,        -- 每天最多生成的题目数量
+    monthly_questions INTEGER NULL,      -- 每月最多生成的题目数量
+    daily_tokens INTEGER NULL,           -- 每天最多消耗的 token 数
+    monthly_tokens INTEGER NULL,         -- 每月最多消耗的 token 数
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    );
+
+-- AI调用记录产出的合格题目数量（用于统计题目配额）
+ALTER TABLE ai_calls ADD COLUMN questions INTEGER DEFAULT 0
@@ -0,0 +1,16 @@
+-- 创建AI响应缓存表（按模型、模板版本和提示语缓存AI原始输出，相同的生成请求可直接复用）
+CREATE TABLE IF NOT EXISTS ai_response_cache (
+    cache_key VARCHAR(64) PRIMARY KEY,     -- 缓存键（模型、模板版本和提示语的 SHA-256）
+    ai_model VARCHAR(50) NOT NULL,         -- 生成内容的模型
+    template_version VARCHAR(100) DEFAULT '', -- 使用的提示语模板版本
+    content TEXT NOT NULL,                 -- AI原始输出
+    hits INTEGER DEFAULT 0,                -- 命中次数
+    expires_at DATETIME NOT NULL,          -- 过期时间
+    last_used_at DATETIME NOT NULL,        -- 最近写入或命中的时间
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    );
+
+CREATE INDEX IF NOT EXISTS idx_ai_response_cache_last_used_at ON ai_response_cache(last_used_at);
+
+-- AI调用记录的缓存使用情况（hit/miss，未使用缓存时为空）
+ALTER TABLE ai_calls ADD COLUMN cache VARCHAR(10) DEFAULT ''
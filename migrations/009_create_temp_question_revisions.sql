@@ -0,0 +1,15 @@
+-- 创建临时题目历史版本表（重新生成单道题目前保存原版本，确认入库前可撤销）
+CREATE TABLE IF NOT EXISTS temp_question_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    temp_question_id INTEGER NOT NULL,   -- 临时题目记录ID
+    revision INTEGER NOT NULL,           -- 该版本的修订号
+    content TEXT NOT NULL,               -- 该版本的题目内容（JSON）
+    instruction TEXT,                    -- 生成下一版本时的修改要求
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (temp_question_id) REFERENCES temp_questions(id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_temp_question_revisions_temp_question_id ON temp_question_revisions(temp_question_id);
+
+-- 临时题目的修订号（每次重新生成加1）
+ALTER TABLE temp_questions ADD COLUMN revision INTEGER DEFAULT 0
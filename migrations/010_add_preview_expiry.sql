@@ -0,0 +1,14 @@
+-- 临时题目的过期时间（超过有效期未确认、被清理任务软删除时设置）
+ALTER TABLE temp_questions ADD COLUMN expired_at DATETIME NULL;
+
+CREATE INDEX IF NOT EXISTS idx_temp_questions_created_at ON temp_questions(created_at);
+
+-- 创建清理任务执行记录表
+CREATE TABLE IF NOT EXISTS janitor_runs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at DATETIME NOT NULL,        -- 开始时间
+    duration_ms INTEGER DEFAULT 0,       -- 耗时（毫秒）
+    expired INTEGER DEFAULT 0,           -- 本次过期（软删除）的临时题目数量
+    purged INTEGER DEFAULT 0,            -- 本次彻底删除的临时题目数量
+    error TEXT                           -- 失败原因
+    )
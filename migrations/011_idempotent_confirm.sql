@@ -0,0 +1,18 @@
+-- 临时题目确认入库后对应的正式题目ID（重复确认同一道题目时直接返回，不重复入库）
+ALTER TABLE temp_questions ADD COLUMN question_id INTEGER NULL;
+
+-- 创建幂等键表（确认入库接口的 Idempotency-Key 请求头，同一个键重复提交时返回首次请求的响应）
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id INTEGER NOT NULL,              -- 用户ID
+    key VARCHAR(255) NOT NULL,             -- 幂等键（同一用户内唯一）
+    request_hash VARCHAR(64) NOT NULL,     -- 接口和请求参数的 SHA-256
+    status VARCHAR(20) NOT NULL,           -- 处理状态（processing/completed）
+    response_code INTEGER DEFAULT 0,       -- 首次请求的响应状态码
+    response_message VARCHAR(255) DEFAULT '', -- 首次请求的响应消息
+    response_data TEXT,                    -- 首次请求的响应数据（JSON）
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, key)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at)
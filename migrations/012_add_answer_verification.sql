@@ -0,0 +1,10 @@
+-- 临时题目的答案校验结果（校验模型独立作答后与生成的答案比对）
+ALTER TABLE temp_questions ADD COLUMN verification_status VARCHAR(20) DEFAULT 'unverified';
+ALTER TABLE temp_questions ADD COLUMN reviewer_model VARCHAR(50) DEFAULT '';
+ALTER TABLE temp_questions ADD COLUMN reviewer_answer TEXT;
+ALTER TABLE temp_questions ADD COLUMN reviewer_reasoning TEXT;
+
+-- 正式题目的答案校验状态（确认入库时沿用临时题目的校验状态）
+ALTER TABLE questions ADD COLUMN verification_status VARCHAR(20) DEFAULT 'unverified';
+
+CREATE INDEX IF NOT EXISTS idx_questions_verification_status ON questions(verification_status)
@@ -0,0 +1,30 @@
+-- 创建出题资料表（用户上传的源码文件、讲义等，按资料内容生成题目）
+CREATE TABLE IF NOT EXISTS source_documents (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,            -- 上传者ID
+    name VARCHAR(255) NOT NULL,          -- 资料名称（如文件名）
+    content TEXT NOT NULL,               -- 资料内容（UTF-8 文本）
+    size INTEGER NOT NULL,               -- 内容大小（字节）
+    lines INTEGER NOT NULL,              -- 内容行数
+    checksum VARCHAR(64) NOT NULL,       -- 内容的 SHA-256
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_source_documents_user_id ON source_documents(user_id);
+CREATE INDEX IF NOT EXISTS idx_source_documents_deleted_at ON source_documents(deleted_at);
+
+-- 临时题目的出处（按资料生成时记录资料ID、资料名称和行号范围）
+ALTER TABLE temp_questions ADD COLUMN source_id INTEGER;
+ALTER TABLE temp_questions ADD COLUMN source_path VARCHAR(255) DEFAULT '';
+ALTER TABLE temp_questions ADD COLUMN source_start_line INTEGER DEFAULT 0;
+ALTER TABLE temp_questions ADD COLUMN source_end_line INTEGER DEFAULT 0;
+
+-- 正式题目的出处（确认入库时沿用临时题目的出处）
+ALTER TABLE questions ADD COLUMN source_id INTEGER;
+ALTER TABLE questions ADD COLUMN source_path VARCHAR(255) DEFAULT '';
+ALTER TABLE questions ADD COLUMN source_start_line INTEGER DEFAULT 0;
+ALTER TABLE questions ADD COLUMN source_end_line INTEGER DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_questions_source_id ON questions(source_id)
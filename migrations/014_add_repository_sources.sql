@@ -0,0 +1,5 @@
+-- 出题资料的类型（upload：上传的文本资料；repository：服务器上的本地仓库，生成时重新扫描）
+ALTER TABLE source_documents ADD COLUMN kind VARCHAR(20) DEFAULT 'upload';
+
+-- 本地仓库的绝对路径（题目出处中的文件路径相对于该路径）
+ALTER TABLE source_documents ADD COLUMN repo_path VARCHAR(1024) DEFAULT ''
@@ -0,0 +1,15 @@
+-- 创建配额预占表（进行中的生成请求预占的题目数量和预估 token 数，多个实例共享，重启后仍有效）
+CREATE TABLE IF NOT EXISTS quota_reservations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,            -- 用户ID
+    questions INTEGER NOT NULL,          -- 预占的题目数量
+    tokens INTEGER NOT NULL,             -- 预占的 token 数（按题目数量预估）
+    job_id INTEGER NULL,                 -- 异步任务ID（任务排队或执行中时预占有效）
+    expires_at DATETIME NULL,            -- 同步请求预占的过期时间（进程异常退出未结算时不再计入）
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_quota_reservations_user_id ON quota_reservations(user_id);
+
+CREATE INDEX IF NOT EXISTS idx_quota_reservations_job_id ON quota_reservations(job_id)
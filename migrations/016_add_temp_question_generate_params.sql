@@ -0,0 +1,2 @@
+-- 临时题目的生成参数（JSON，含关键词数组和降级模型列表，重新生成单道题目时按原请求还原）
+ALTER TABLE temp_questions ADD COLUMN generate_params TEXT DEFAULT ''
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- 幂等键按最后更新时间清理（超时后被重新占用的键创建时间较早但仍在处理中）
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_updated_at ON idempotency_keys(updated_at)
\ No newline at end of file
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+)
+
+// AI调用状态
+const (
+	AICallStatusSuccess = "success" // 调用成功
+	AICallStatusError   = "error"   // 调用失败
+)
+
+// AI调用用途
+const (
+	AICallPurposeGenerate   = "generate"   // 生成题目
+	AICallPurposeStream     = "stream"     // 流式生成题目
+	AICallPurposeReprompt   = "reprompt"   // 修正校验未通过的题目
+	AICallPurposeRegenerate = "regenerate" // 重新生成预览中的单道题目
+	AICallPurposeVerify     = "verify"     // 校验模型独立作答，校验生成的答案
+)
+
+// 响应缓存的使用情况（未启用缓存或请求跳过缓存时为空）
+const (
+	AICallCacheHit  = "hit"  // 命中缓存，未实际调用模型
+	AICallCacheMiss = "miss" // 未命中缓存，调用模型后写入缓存
+)
+
+// AICall 对应数据库中的 ai_calls 表（每次AI调用的用量和费用记录）
+type AICall struct {
+	ID               int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID           int64     `gorm:"not null" json:"user_id"`                            // 发起调用的用户ID
+	AiModel          string    `gorm:"type:VARCHAR(50);not null" json:"ai_model"`          // 调用的模型
+	Purpose          string    `gorm:"type:VARCHAR(20);not null" json:"purpose"`           // 调用用途（generate/stream/reprompt）
+	PreviewID        string    `gorm:"type:VARCHAR(64)" json:"preview_id,omitempty"`       // 关联的预览批次ID
+	PromptTokens     int       `gorm:"default:0" json:"prompt_tokens"`                     // 提示语 token 数
+	CompletionTokens int       `gorm:"default:0" json:"completion_tokens"`                 // 生成内容 token 数
+	Questions        int       `gorm:"default:0" json:"questions"`                         // 产出的合格题目数量（含修正后合格的题目，计入题目配额）
+	LatencyMs        int64     `gorm:"default:0" json:"latency_ms"`                        // 调用耗时（毫秒，含重试）
+	Status           string    `gorm:"type:VARCHAR(20);not null" json:"status"`            // 调用状态（success/error）
+	Error            string    `gorm:"type:text" json:"error,omitempty"`                   // 失败原因
+	Cost             float64   `gorm:"default:0" json:"cost"`                              // 按配置单价估算的费用
+	Cache            string    `gorm:"type:VARCHAR(10);default:''" json:"cache,omitempty"` // 响应缓存（hit/miss，未使用缓存时为空）
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 显式指定表名
+func (AICall) TableName() string {
+	return "ai_calls"
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// AIResponseCache 对应数据库中的 ai_response_cache 表（按模型、模板版本和提示语缓存的AI原始输出）
+type AIResponseCache struct {
+	CacheKey        string    `gorm:"type:VARCHAR(64);primaryKey" json:"cache_key"`         // 缓存键（模型、模板版本和提示语的 SHA-256）
+	AiModel         string    `gorm:"type:VARCHAR(50);not null" json:"ai_model"`            // 生成内容的模型
+	TemplateVersion string    `gorm:"type:VARCHAR(100);default:''" json:"template_version"` // 使用的提示语模板版本
+	Content         string    `gorm:"type:text;not null" json:"content"`                    // AI原始输出
+	Hits            int       `gorm:"default:0" json:"hits"`                                // 命中次数
+	ExpiresAt       time.Time `gorm:"not null" json:"expires_at"`                           // 过期时间
+	LastUsedAt      time.Time `gorm:"not null" json:"last_used_at"`                         // 最近写入或命中的时间（超出容量时优先淘汰最久未使用的）
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 显式指定表名
+func (AIResponseCache) TableName() string {
+	return "ai_response_cache"
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+)
+
+// 生成任务状态
+const (
+	JobStatusQueued    = "queued"    // 排队中
+	JobStatusRunning   = "running"   // 执行中
+	JobStatusSucceeded = "succeeded" // 成功
+	JobStatusPartial   = "partial"   // 部分成功（部分批次失败，或服务重启时任务被中断）
+	JobStatusFailed    = "failed"    // 失败
+)
+
+// GenerationJob 对应数据库中的 generation_jobs 表（异步题目生成任务）
+type GenerationJob struct {
+	ID           int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       int64      `gorm:"not null" json:"user_id"`                     // 提交任务的用户ID
+	Status       string     `gorm:"type:VARCHAR(20);not null" json:"status"`     // 任务状态（queued/running/succeeded/partial/failed）
+	Request      string     `gorm:"type:text;not null" json:"request"`           // 生成请求参数（JSON格式）
+	PreviewID    string     `gorm:"type:VARCHAR(64);not null" json:"preview_id"` // 预览批次ID（提交时分配）
+	AiModel      string     `gorm:"type:VARCHAR(50)" json:"ai_model,omitempty"`  // 实际生成题目的模型
+	FallbackUsed bool       `gorm:"default:false" json:"fallback_used"`          // 是否发生了模型降级
+	Attempts     string     `gorm:"type:text" json:"attempts,omitempty"`         // 各模型的尝试记录（JSON格式）
+	Result       string     `gorm:"type:text" json:"result,omitempty"`           // 生成结果（JSON格式，不含题目）
+	Error        string     `gorm:"type:text" json:"error,omitempty"`            // 失败原因（部分成功时为未全部生成的原因）
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`  // 开始执行时间
+	FinishedAt   *time.Time `json:"finished_at,omitempty"` // 结束时间
+}
+
+// TableName 显式指定表名
+func (GenerationJob) TableName() string {
+	return "generation_jobs"
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// 幂等键的处理状态
+const (
+	IdempotencyStatusProcessing = "processing" // 请求处理中
+	IdempotencyStatusCompleted  = "completed"  // 已处理完成（保存了响应）
+)
+
+// IdempotencyKey 对应数据库中的 idempotency_keys 表（客户端通过 Idempotency-Key 请求头提交的幂等键及首次请求的响应）
+type IdempotencyKey struct {
+	UserID          int64     `gorm:"primaryKey;autoIncrement:false" json:"user_id"`        // 用户ID
+	Key             string    `gorm:"type:VARCHAR(255);primaryKey" json:"key"`              // 幂等键（同一用户内唯一）
+	RequestHash     string    `gorm:"type:VARCHAR(64);not null" json:"request_hash"`        // 接口和请求参数的 SHA-256（同一个键不能用于不同的请求）
+	Status          string    `gorm:"type:VARCHAR(20);not null" json:"status"`              // 处理状态（processing/completed）
+	ResponseCode    int       `gorm:"default:0" json:"response_code"`                       // 首次请求的响应状态码
+	ResponseMessage string    `gorm:"type:VARCHAR(255);default:''" json:"response_message"` // 首次请求的响应消息
+	ResponseData    string    `gorm:"type:text" json:"response_data"`                       // 首次请求的响应数据（JSON）
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 显式指定表名
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
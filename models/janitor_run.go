@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// JanitorRun 对应数据库中的 janitor_runs 表（过期预览清理任务的执行记录）
+type JanitorRun struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	StartedAt  time.Time `gorm:"not null" json:"started_at"`       // 开始时间
+	DurationMs int64     `gorm:"default:0" json:"duration_ms"`     // 耗时（毫秒）
+	Expired    int64     `gorm:"default:0" json:"expired"`         // 本次过期（软删除）的临时题目数量
+	Purged     int64     `gorm:"default:0" json:"purged"`          // 本次彻底删除的临时题目数量
+	Error      string    `gorm:"type:text" json:"error,omitempty"` // 失败原因
+}
+
+// TableName 显式指定表名
+func (JanitorRun) TableName() string {
+	return "janitor_runs"
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// 提示语模板来源
+const (
+	TemplateSourceFile = "file" // 启动时从模板目录导入
+	TemplateSourceAPI  = "api"  // 管理员通过接口创建
+)
+
+// PromptTemplate 对应数据库中的 prompt_templates 表（题目生成提示语模板）
+type PromptTemplate struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name         string    `gorm:"type:VARCHAR(100);not null" json:"name"`           // 模板名称
+	Version      int       `gorm:"not null" json:"version"`                          // 版本号（同名模板内递增）
+	Language     string    `gorm:"type:VARCHAR(50);default:''" json:"language"`      // 适用的编程语言（空表示不限）
+	QuestionType string    `gorm:"type:VARCHAR(20);default:''" json:"question_type"` // 适用的题型（空表示不限）
+	AiModel      string    `gorm:"type:VARCHAR(50);default:''" json:"ai_model"`      // 适用的AI模型（空表示不限）
+	Content      string    `gorm:"type:text;not null" json:"content"`                // 模板内容（text/template 语法）
+	Active       bool      `gorm:"default:false" json:"active"`                      // 是否为该名称的激活版本
+	Source       string    `gorm:"type:VARCHAR(20);not null" json:"source"`          // 来源（file/api）
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 显式指定表名
+func (PromptTemplate) TableName() string {
+	return "prompt_templates"
+}
@@ -6,22 +6,54 @@ import (
 	"gorm.io/gorm"
 )
 
+// 题型
+const (
+	QuestionTypeSingle      = "single"       // 单选题
+	QuestionTypeMultiple    = "multiple"     // 多选题
+	QuestionTypeTrueFalse   = "true_false"   // 判断题
+	QuestionTypeFillBlank   = "fill_blank"   // 填空题
+	QuestionTypeShortAnswer = "short_answer" // 简答题
+	QuestionTypeCodeOutput  = "code_output"  // 代码输出题（阅读代码片段，写出标准输出）
+)
+
+// 题目难度
+const (
+	DifficultyEasy   = "easy"   // 简单
+	DifficultyMedium = "medium" // 中等
+	DifficultyHard   = "hard"   // 困难
+)
+
+// 答案校验状态（由校验模型独立作答后与生成的答案比对）
+const (
+	VerificationUnverified = "unverified" // 未校验（未开启校验、题型不支持或校验失败）
+	VerificationVerified   = "verified"   // 校验模型的答案与生成的答案一致
+	VerificationFlagged    = "flagged"    // 校验模型的答案与生成的答案不一致，需人工复核
+)
+
 // Question 对应数据库中的 questions 表
 type Question struct {
-	ID           int64          `gorm:"primaryKey;autoIncrement" json:"id"`
-	Title        string         `gorm:"type:text;not null" json:"title"`
-	QuestionType string         `gorm:"type:VARCHAR(20);not null" json:"question_type"` // 'single' 或 'multiple'
-	Options      string         `gorm:"type:text;not null" json:"options"`              // JSON格式存储选项
-	Answer       string         `gorm:"type:text;not null" json:"answer"`
-	Explanation  string         `gorm:"type:text" json:"explanation,omitempty"`
-	Keywords     string         `gorm:"type:VARCHAR(255)" json:"keywords,omitempty"`
-	Language     string         `gorm:"type:VARCHAR(50);not null" json:"language"`
-	AiModel      string         `gorm:"type:VARCHAR(50);not null" json:"ai_model"`
-	UserID       int64          `gorm:"not null" json:"user_id"`
-	User         User           `gorm:"foreignKey:UserID" json:"user,omitempty"` // 关联用户表
-	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID                 int64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Title              string         `gorm:"type:text;not null" json:"title"`
+	QuestionType       string         `gorm:"type:VARCHAR(20);not null" json:"question_type"`             // 题型（见 QuestionType* 常量）
+	Options            string         `gorm:"type:text;not null" json:"options"`                          // JSON格式存储选项（非选择题为 []）
+	Answer             string         `gorm:"type:text;not null" json:"answer"`                           // 答案（格式因题型而异，见 README）
+	CodeSnippet        string         `gorm:"type:text;default:''" json:"code_snippet,omitempty"`         // 代码片段（可选，code_output 题型必填）
+	CodeLanguage       string         `gorm:"type:VARCHAR(50);default:''" json:"code_language,omitempty"` // 代码片段的语言标记（如 go、python）
+	Explanation        string         `gorm:"type:text" json:"explanation,omitempty"`
+	Keywords           string         `gorm:"type:VARCHAR(255)" json:"keywords,omitempty"`
+	Language           string         `gorm:"type:VARCHAR(50);not null" json:"language"`
+	AiModel            string         `gorm:"type:VARCHAR(50);not null" json:"ai_model"`
+	Difficulty         string         `gorm:"type:VARCHAR(10);default:''" json:"difficulty"`                    // 难度（easy/medium/hard，空表示未指定）
+	VerificationStatus string         `gorm:"type:VARCHAR(20);default:'unverified'" json:"verification_status"` // 答案校验状态（见 Verification* 常量）
+	SourceID           *int64         `gorm:"default:null" json:"source_id,omitempty"`                          // 出题资料ID（按资料生成时）
+	SourcePath         string         `gorm:"type:VARCHAR(255);default:''" json:"source_path,omitempty"`        // 出处的文件路径或资料名称
+	SourceStartLine    int            `gorm:"default:0" json:"source_start_line,omitempty"`                     // 出处的起始行号（从1开始）
+	SourceEndLine      int            `gorm:"default:0" json:"source_end_line,omitempty"`                       // 出处的结束行号（含）
+	UserID             int64          `gorm:"not null" json:"user_id"`
+	User               User           `gorm:"foreignKey:UserID" json:"user,omitempty"` // 关联用户表
+	CreatedAt          time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 }
 
 // TableName 显式指定表名
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// QuotaReservation 对应数据库中的 quota_reservations 表（进行中的生成请求预占的配额，生成结束后删除）
+// 同步请求的预占在 ExpiresAt 前有效；异步任务的预占关联 JobID，任务排队或执行中时有效
+type QuotaReservation struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int64      `gorm:"not null;index" json:"user_id"` // 用户ID
+	Questions int64      `gorm:"not null" json:"questions"`     // 预占的题目数量
+	Tokens    int64      `gorm:"not null" json:"tokens"`        // 预占的 token 数（按题目数量预估）
+	JobID     *int64     `gorm:"index" json:"job_id"`           // 异步任务ID
+	ExpiresAt *time.Time `json:"expires_at"`                    // 同步请求预占的过期时间
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 显式指定表名
+func (QuotaReservation) TableName() string {
+	return "quota_reservations"
+}
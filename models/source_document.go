@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 出题资料类型
+const (
+	SourceKindUpload     = "upload"     // 上传的文本资料
+	SourceKindRepository = "repository" // 服务器上的本地仓库（生成时重新扫描）
+)
+
+// SourceDocument 对应数据库中的 source_documents 表（出题资料：上传的源码文件、讲义、README，或服务器上的本地仓库）
+type SourceDocument struct {
+	ID        int64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int64          `gorm:"not null" json:"user_id"`                                  // 上传者ID
+	Kind      string         `gorm:"type:VARCHAR(20);default:'upload'" json:"kind"`            // 资料类型（见 SourceKind* 常量）
+	Name      string         `gorm:"type:VARCHAR(255);not null" json:"name"`                   // 资料名称（如文件名，题目的出处中使用）
+	RepoPath  string         `gorm:"type:VARCHAR(1024);default:''" json:"repo_path,omitempty"` // 本地仓库的绝对路径（本地仓库资料）
+	Content   string         `gorm:"type:text;not null" json:"content"`                        // 资料内容（UTF-8 文本；本地仓库为登记时扫描出的主题列表）
+	Size      int            `gorm:"not null" json:"size"`                                     // 内容大小（字节）
+	Lines     int            `gorm:"not null" json:"lines"`                                    // 内容行数
+	Checksum  string         `gorm:"type:VARCHAR(64);not null" json:"checksum"`                // 内容的 SHA-256
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`                         // 上传时间
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`                        // 软删除字段（已生成的题目保留出处）
+}
+
+// TableName 显式指定表名
+func (SourceDocument) TableName() string {
+	return "source_documents"
+}
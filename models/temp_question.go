@@ -8,20 +8,38 @@ import (
 
 // TempQuestion 对应数据库中的 temp_questions 表（临时存储AI生成的未确认题目）
 type TempQuestion struct {
-	ID           int64          `gorm:"primaryKey;autoIncrement" json:"id"`
-	PreviewID    string         `gorm:"type:VARCHAR(64);not null" json:"preview_id"`    // 预览批次ID（UUID）
-	TempID       string         `gorm:"type:VARCHAR(64);not null" json:"temp_id"`       // 单题临时ID
-	Title        string         `gorm:"type:text;not null" json:"title"`                // 题目标题
-	QuestionType string         `gorm:"type:VARCHAR(20);not null" json:"question_type"` // 题目类型（single/multiple）
-	Options      string         `gorm:"type:text;not null" json:"options"`              // 选项（JSON格式字符串）
-	Answer       string         `gorm:"type:text;not null" json:"answer"`               // 答案
-	Explanation  string         `gorm:"type:text" json:"explanation,omitempty"`         // 解析（可选）
-	Keywords     string         `gorm:"type:VARCHAR(255)" json:"keywords,omitempty"`    // 关键词（可选）
-	Language     string         `gorm:"type:VARCHAR(50);not null" json:"language"`      // 编程语言
-	AiModel      string         `gorm:"type:VARCHAR(50);not null" json:"ai_model"`      // 使用的AI模型
-	UserID       int64          `gorm:"not null" json:"user_id"`                        // 关联用户ID
-	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`               // 创建时间
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`              // 软删除字段
+	ID                 int64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	PreviewID          string         `gorm:"type:VARCHAR(64);not null" json:"preview_id"`                      // 预览批次ID（UUID）
+	TempID             string         `gorm:"type:VARCHAR(64);not null" json:"temp_id"`                         // 单题临时ID
+	Title              string         `gorm:"type:text;not null" json:"title"`                                  // 题目标题
+	QuestionType       string         `gorm:"type:VARCHAR(20);not null" json:"question_type"`                   // 题目类型（见 QuestionType* 常量）
+	Options            string         `gorm:"type:text;not null" json:"options"`                                // 选项（JSON格式字符串，非选择题为 []）
+	Answer             string         `gorm:"type:text;not null" json:"answer"`                                 // 答案（格式因题型而异）
+	CodeSnippet        string         `gorm:"type:text;default:''" json:"code_snippet,omitempty"`               // 代码片段（可选，code_output 题型必填）
+	CodeLanguage       string         `gorm:"type:VARCHAR(50);default:''" json:"code_language,omitempty"`       // 代码片段的语言标记（如 go、python）
+	Explanation        string         `gorm:"type:text" json:"explanation,omitempty"`                           // 解析（可选）
+	Keywords           string         `gorm:"type:VARCHAR(255)" json:"keywords,omitempty"`                      // 关键词（可选）
+	GenerateParams     string         `gorm:"type:text;default:''" json:"-"`                                    // 生成参数（JSON，含关键词数组和降级模型列表，重新生成时还原原请求）
+	Language           string         `gorm:"type:VARCHAR(50);not null" json:"language"`                        // 编程语言
+	AiModel            string         `gorm:"type:VARCHAR(50);not null" json:"ai_model"`                        // 使用的AI模型
+	Difficulty         string         `gorm:"type:VARCHAR(10);default:''" json:"difficulty"`                    // 难度（easy/medium/hard，空表示未指定）
+	SimilarQuestionID  *int64         `gorm:"default:null" json:"similar_question_id"`                          // 题库中最相似的正式题目ID（没有可比较的题目时为空）
+	Similarity         float64        `gorm:"default:0" json:"similarity"`                                      // 与最相似题目的相似度（0-1）
+	TemplateVersion    string         `gorm:"type:VARCHAR(100);default:''" json:"template_version"`             // 生成时使用的提示语模板版本（如 default@1）
+	VerificationStatus string         `gorm:"type:VARCHAR(20);default:'unverified'" json:"verification_status"` // 答案校验状态（见 Verification* 常量）
+	ReviewerModel      string         `gorm:"type:VARCHAR(50);default:''" json:"reviewer_model,omitempty"`      // 校验模型
+	ReviewerAnswer     string         `gorm:"type:text" json:"reviewer_answer,omitempty"`                       // 校验模型独立作答的答案（存储格式）
+	ReviewerReasoning  string         `gorm:"type:text" json:"reviewer_reasoning,omitempty"`                    // 校验模型的作答理由（答案不一致时供人工复核）
+	SourceID           *int64         `gorm:"default:null" json:"source_id,omitempty"`                          // 出题资料ID（按资料生成时）
+	SourcePath         string         `gorm:"type:VARCHAR(255);default:''" json:"source_path,omitempty"`        // 出处的文件路径或资料名称
+	SourceStartLine    int            `gorm:"default:0" json:"source_start_line,omitempty"`                     // 出处的起始行号（从1开始）
+	SourceEndLine      int            `gorm:"default:0" json:"source_end_line,omitempty"`                       // 出处的结束行号（含）
+	Revision           int            `gorm:"default:0" json:"revision"`                                        // 修订号（每次重新生成加1，撤销时恢复）
+	QuestionID         *int64         `gorm:"default:null" json:"question_id,omitempty"`                        // 确认入库后对应的正式题目ID（重复确认时直接返回）
+	UserID             int64          `gorm:"not null" json:"user_id"`                                          // 关联用户ID
+	CreatedAt          time.Time      `gorm:"autoCreateTime" json:"created_at"`                                 // 创建时间
+	ExpiredAt          *time.Time     `gorm:"default:null" json:"expired_at,omitempty"`                         // 过期时间（超过有效期未确认、被清理任务软删除时设置）
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`                                // 软删除字段
 }
 
 // TableName 显式指定表名
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// TempQuestionRevision 对应数据库中的 temp_question_revisions 表（临时题目重新生成前的历史版本，确认入库前可撤销）
+type TempQuestionRevision struct {
+	ID             int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TempQuestionID int64     `gorm:"not null" json:"temp_question_id"`       // 临时题目记录ID（temp_questions.id）
+	Revision       int       `gorm:"not null" json:"revision"`               // 该版本的修订号
+	Content        string    `gorm:"type:text;not null" json:"content"`      // 该版本的题目内容（JSON）
+	Instruction    string    `gorm:"type:text" json:"instruction,omitempty"` // 生成下一版本时的修改要求
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 显式指定表名
+func (TempQuestionRevision) TableName() string {
+	return "temp_question_revisions"
+}
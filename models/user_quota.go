@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// UserQuota 对应数据库中的 user_quotas 表（单个用户的生成配额，覆盖角色默认配额）
+// 各项为空时使用角色默认配额，0 表示不限
+type UserQuota struct {
+	UserID           int64     `gorm:"primaryKey;autoIncrement:false" json:"user_id"` // 用户ID
+	DailyQuestions   *int64    `json:"daily_questions"`                               // 每天最多生成的题目数量
+	MonthlyQuestions *int64    `json:"monthly_questions"`                             // 每月最多生成的题目数量
+	DailyTokens      *int64    `json:"daily_tokens"`                                  // 每天最多消耗的 token 数
+	MonthlyTokens    *int64    `json:"monthly_tokens"`                                // 每月最多消耗的 token 数
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 显式指定表名
+func (UserQuota) TableName() string {
+	return "user_quotas"
+}
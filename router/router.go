@@ -16,12 +16,29 @@ func InitRouter() *gin.Engine {
 	r.PUT("/api/users/:id", middlewares.AuthMiddleware(), controllers.UpdateUser)
 
 	questionGroup := r.Group("api/questions", middlewares.AuthMiddleware())
+	questionGroup.GET("/models", controllers.GetAIModels)
 	questionGroup.POST("/generate", controllers.GenerateQuestions)
+	questionGroup.POST("/generate/stream", controllers.GenerateQuestionsStream)
+	questionGroup.GET("/jobs/:id", controllers.GetGenerationJob)
 	questionGroup.POST("/confirm", controllers.ConfirmQuestions)
+	questionGroup.GET("/previews", controllers.ListPreviews)
+	questionGroup.GET("/previews/:preview_id", controllers.GetPreview)
+	questionGroup.DELETE("/previews/:preview_id", controllers.DeletePreview)
+	questionGroup.PATCH("/previews/:preview_id/items/:temp_id", controllers.UpdateTempQuestion)
+	questionGroup.POST("/previews/:preview_id/items/:temp_id/regenerate", controllers.RegenerateTempQuestion)
+	questionGroup.POST("/previews/:preview_id/items/:temp_id/undo", controllers.UndoTempQuestion)
 	questionGroup.GET("", controllers.GetQuestions)
+	questionGroup.GET("/duplicates", controllers.GetDuplicateQuestions)
 	questionGroup.PUT("/:id", controllers.UpdateQuestion)
 	questionGroup.DELETE("/:id", controllers.DeleteQuestion)
 
+	sourceGroup := r.Group("api/sources", middlewares.AuthMiddleware())
+	sourceGroup.GET("", controllers.ListSources)
+	sourceGroup.POST("", controllers.UploadSource)
+	sourceGroup.POST("/repository", middlewares.AdminMiddleware(), controllers.RegisterRepositorySource)
+	sourceGroup.GET("/:id", controllers.GetSource)
+	sourceGroup.DELETE("/:id", controllers.DeleteSource)
+
 	paperGroup := r.Group("api/papers", middlewares.AuthMiddleware())
 	paperGroup.GET("", controllers.GetPapers)
 	paperGroup.POST("", controllers.CreatePaper)
@@ -32,6 +49,22 @@ func InitRouter() *gin.Engine {
 	paperGroup.PUT("/:id/questions/order", controllers.UpdateQuestionOrder)
 	paperGroup.PUT("/:id", controllers.UpdatePaper)
 
+	r.GET("/api/ai/breakers", middlewares.AuthMiddleware(), middlewares.AdminMiddleware(), controllers.GetAIBreakers)
+	r.GET("/api/ai/usage", middlewares.AuthMiddleware(), middlewares.AdminMiddleware(), controllers.GetAIUsage)
+
+	templateGroup := r.Group("api/prompt-templates", middlewares.AuthMiddleware(), middlewares.AdminMiddleware())
+	templateGroup.GET("", controllers.ListPromptTemplates)
+	templateGroup.POST("", controllers.CreatePromptTemplate)
+	templateGroup.POST("/:id/preview", controllers.PreviewPromptTemplate)
+	templateGroup.PUT("/:id/activate", controllers.ActivatePromptTemplate)
+
+	quotaGroup := r.Group("api/quotas", middlewares.AuthMiddleware())
+	quotaGroup.GET("/me", controllers.GetMyQuota)
+	quotaGroup.GET("", middlewares.AdminMiddleware(), controllers.ListQuotas)
+	quotaGroup.GET("/users/:id", middlewares.AdminMiddleware(), controllers.GetUserQuota)
+	quotaGroup.PUT("/users/:id", middlewares.AdminMiddleware(), controllers.UpdateUserQuota)
+	quotaGroup.DELETE("/users/:id", middlewares.AdminMiddleware(), controllers.DeleteUserQuota)
+
 	r.GET("/api/statistics/user/:id", middlewares.AuthMiddleware(), controllers.GetUserStatistics)
 	r.GET("/api/statistics/overview", middlewares.AuthMiddleware(), middlewares.AdminMiddleware(), controllers.GetStatisticsOverview)
 	return r
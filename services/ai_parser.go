@@ -0,0 +1,253 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ItemParseError 单道题目的解析错误（不影响同批次其他题目）
+type ItemParseError struct {
+	Index int    `json:"index"`         // 题目在AI输出中的序号（从0开始）
+	Error string `json:"error"`         // 错误原因
+	Raw   string `json:"raw,omitempty"` // 原始内容
+}
+
+// 包裹题目数组时常用的字段名（按优先级）
+var questionArrayKeys = []string{"questions", "data", "items", "result"}
+
+// codeFencePattern 匹配 markdown 代码块（```json ... ```），结尾缺失时匹配到末尾
+var codeFencePattern = regexp.MustCompile("(?s)```[a-zA-Z]*[ \\t]*\\r?\\n?(.*?)(?:```|$)")
+
+// maxRawLength 错误信息中保留的原始内容最大长度
+const maxRawLength = 200
+
+// extractAIQuestions 从AI输出中提取题目：
+// 1. 忽略 markdown 代码块和前后的说明文字；
+// 2. 兼容 [...]、{"questions":[...]}（或其他数组字段）以及单个题目对象；
+// 3. 整体JSON不合法时（如输出被截断），逐个抢救数组中完整的题目对象。
+// 单道题目解析失败记录到 itemErrs；找不到任何JSON内容时返回 err
+func extractAIQuestions(content string) (questions []aiQuestion, itemErrs []ItemParseError, err error) {
+	// 1. 按完整JSON解析（题目内容中可能包含代码块标记，先按原文解析，再去掉代码块解析）
+	items, ok := decodeQuestionItems(content)
+	if !ok {
+		items, ok = decodeQuestionItems(stripCodeFence(content))
+	}
+
+	// 2. 整体解析失败时抢救完整的题目对象（代码块标记和说明文字不在数组内，不影响提取）
+	if !ok {
+		var objects jsonObjectStream
+		items = objects.Write(content)
+		if len(items) == 0 && objects.Open() {
+			return nil, nil, fmt.Errorf("响应被截断，未包含完整的题目，响应内容：%s", truncate(content, maxRawLength))
+		}
+		if len(items) == 0 {
+			return nil, nil, fmt.Errorf("未找到有效的JSON题目内容，响应内容：%s", truncate(content, maxRawLength))
+		}
+		if objects.Open() {
+			// 最后一道题目不完整，记录为解析错误
+			itemErrs = append(itemErrs, ItemParseError{
+				Index: len(items),
+				Error: "响应被截断或格式错误，该题目不完整",
+			})
+		}
+	}
+
+	// 3. 逐个解析题目
+	for i, item := range items {
+		var aq aiQuestion
+		if err := json.Unmarshal([]byte(item), &aq); err != nil {
+			itemErrs = append(itemErrs, ItemParseError{Index: i, Error: err.Error(), Raw: truncate(item, maxRawLength)})
+			continue
+		}
+		questions = append(questions, aq)
+	}
+	sort.SliceStable(itemErrs, func(a, b int) bool { return itemErrs[a].Index < itemErrs[b].Index })
+
+	return questions, itemErrs, nil
+}
+
+// stripCodeFence 去掉 markdown 代码块标记，没有代码块时原样返回。
+// 代码块必须出现在JSON内容之前，题目内容（如 code_snippet）中的代码块标记不受影响
+func stripCodeFence(content string) string {
+	fence := strings.Index(content, "```")
+	if fence < 0 || fence > strings.IndexAny(content, "[{") {
+		return content
+	}
+	if m := codeFencePattern.FindStringSubmatch(content); m != nil && strings.ContainsAny(m[1], "[{") {
+		return m[1]
+	}
+	return content
+}
+
+// decodeQuestionItems 依次解析载荷中的顶层JSON值（允许前后有说明文字），返回第一个题目列表。
+// 某个JSON值不合法时停止（交由调用方抢救），不会误把数组中的单个题目当作完整结果
+func decodeQuestionItems(payload string) ([]string, bool) {
+	for i := 0; i < len(payload); i++ {
+		if payload[i] != '[' && payload[i] != '{' {
+			continue
+		}
+		decoder := json.NewDecoder(strings.NewReader(payload[i:]))
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, false
+		}
+		if items, ok := questionItems(value); ok {
+			return items, true
+		}
+		// 跳过这个不是题目的JSON值（如说明文字中的 [1]）
+		i += int(decoder.InputOffset()) - 1
+	}
+	return nil, false
+}
+
+// questionItems 识别题目数组：数组本身、对象中的数组字段或单个题目对象
+func questionItems(value json.RawMessage) ([]string, bool) {
+	// 1. 数组（元素中至少有一个对象，避免把说明文字中的 [1] 之类误认为题目）
+	if items, ok := objectArray(value); ok {
+		return items, true
+	}
+
+	var object map[string]json.RawMessage
+	if json.Unmarshal(value, &object) != nil {
+		return nil, false
+	}
+
+	// 2. 单个题目对象
+	if _, ok := object["title"]; ok {
+		return []string{string(value)}, true
+	}
+
+	// 3. 包裹对象：优先常用字段名，其次按字段名顺序取第一个数组字段
+	keys := make([]string, 0, len(questionArrayKeys)+len(object))
+	keys = append(keys, questionArrayKeys...)
+	others := make([]string, 0, len(object))
+	for key := range object {
+		others = append(others, key)
+	}
+	sort.Strings(others)
+	keys = append(keys, others...)
+	for _, key := range keys {
+		if field, ok := object[key]; ok {
+			if items, ok := objectArray(field); ok {
+				return items, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// objectArray 将JSON数组拆分为元素原文（空数组或包含对象元素时才视为题目数组）
+func objectArray(value json.RawMessage) ([]string, bool) {
+	var array []json.RawMessage
+	if json.Unmarshal(value, &array) != nil {
+		return nil, false
+	}
+	items := make([]string, len(array))
+	hasObject := len(array) == 0
+	for i, item := range array {
+		items[i] = string(item)
+		if strings.HasPrefix(strings.TrimSpace(items[i]), "{") {
+			hasObject = true
+		}
+	}
+	return items, hasObject
+}
+
+// truncate 截断过长的字符串（按字符）
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
+
+// errNoValidQuestions 生成结果中没有可用题目
+func errNoValidQuestions(itemErrs []ItemParseError, rejected []RejectedQuestion) error {
+	switch {
+	case len(rejected) > 0:
+		return fmt.Errorf("AI未生成任何有效题目（%d道校验未通过，首个问题：%s）", len(rejected), strings.Join(rejected[0].Problems, "；"))
+	case len(itemErrs) > 0:
+		return fmt.Errorf("AI未生成任何有效题目（%d道解析失败，首个错误：%s）", len(itemErrs), itemErrs[0].Error)
+	default:
+		return errors.New("AI未生成任何题目")
+	}
+}
+
+// jsonObjectStream 增量JSON对象提取器：逐段写入模型输出，
+// 每当数组中的一个题目对象完整闭合时将其原文返回（兼容 [...] 和 {"questions":[...]} 两种格式）。
+// 题目对象内部嵌套的对象不会单独返回
+type jsonObjectStream struct {
+	buf        strings.Builder // 已接收的全部内容
+	stack      []openBracket   // 未闭合的括号
+	candidates int             // 未闭合的题目对象数量
+	inString   bool            // 是否处于字符串内
+	escaped    bool            // 上一个字符是否为转义符
+}
+
+// openBracket 未闭合的括号
+type openBracket struct {
+	ch        byte // '{' 或 '['
+	start     int  // 在已接收内容中的位置
+	candidate bool // 是否为题目对象（父容器为数组且不在其他题目对象内）
+}
+
+// Write 写入一段增量内容，返回本段内容中闭合的完整题目对象
+func (s *jsonObjectStream) Write(delta string) []string {
+	var objects []string
+	offset := s.buf.Len()
+	s.buf.WriteString(delta)
+
+	for i := 0; i < len(delta); i++ {
+		ch := delta[i]
+
+		// 1. 字符串内只关心转义和结束引号
+		if s.inString {
+			switch {
+			case s.escaped:
+				s.escaped = false
+			case ch == '\\':
+				s.escaped = true
+			case ch == '"':
+				s.inString = false
+			}
+			continue
+		}
+
+		// 2. 括号入栈/出栈
+		switch ch {
+		case '"':
+			s.inString = true
+		case '{', '[':
+			candidate := ch == '{' && s.candidates == 0 && len(s.stack) > 0 && s.stack[len(s.stack)-1].ch == '['
+			if candidate {
+				s.candidates++
+			}
+			s.stack = append(s.stack, openBracket{ch: ch, start: offset + i, candidate: candidate})
+		case '}', ']':
+			if len(s.stack) == 0 {
+				continue
+			}
+			open := s.stack[len(s.stack)-1]
+			s.stack = s.stack[:len(s.stack)-1]
+
+			// 3. 题目对象闭合时输出
+			if open.candidate {
+				s.candidates--
+				if ch == '}' {
+					objects = append(objects, s.buf.String()[open.start:offset+i+1])
+				}
+			}
+		}
+	}
+	return objects
+}
+
+// Open 是否还有未闭合的题目对象（即输出在题目中途被截断）
+func (s *jsonObjectStream) Open() bool {
+	return s.candidates > 0
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractAIQuestions(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantTitles []string // 解析成功的题目标题（按顺序）
+		wantErrs   []int    // 解析失败的题目序号（按顺序）
+		wantErr    bool     // 是否整体解析失败
+	}{
+		{
+			name:       "纯JSON数组",
+			content:    `[{"title":"A","answer":"A"},{"title":"B","answer":"B"}]`,
+			wantTitles: []string{"A", "B"},
+		},
+		{
+			name:       "空数组",
+			content:    `[]`,
+			wantTitles: nil,
+		},
+		{
+			name:       "markdown代码块",
+			content:    "```json\n[{\"title\":\"A\"},{\"title\":\"B\"}]\n```",
+			wantTitles: []string{"A", "B"},
+		},
+		{
+			name:       "代码块缺少结尾标记",
+			content:    "```json\n[{\"title\":\"A\"}]",
+			wantTitles: []string{"A"},
+		},
+		{
+			name:       "前后有说明文字",
+			content:    "好的，以下是题目：\n[{\"title\":\"A\"}]\n希望对你有帮助。",
+			wantTitles: []string{"A"},
+		},
+		{
+			name:       "说明文字中的非题目JSON",
+			content:    "参考资料[1]如下：[{\"title\":\"A\"}]",
+			wantTitles: []string{"A"},
+		},
+		{
+			name:       "questions字段包裹",
+			content:    `{"questions":[{"title":"A"},{"title":"B"}]}`,
+			wantTitles: []string{"A", "B"},
+		},
+		{
+			name:       "其他数组字段包裹",
+			content:    `{"count":1,"list":[{"title":"A"}]}`,
+			wantTitles: []string{"A"},
+		},
+		{
+			name:       "单个题目对象",
+			content:    `{"title":"A","answer":"A"}`,
+			wantTitles: []string{"A"},
+		},
+		{
+			name:       "题目内容中的代码块标记不受影响",
+			content:    "[{\"title\":\"A\",\"code_snippet\":\"```go\\nfmt.Println(1)\\n```\"}]",
+			wantTitles: []string{"A"},
+		},
+		{
+			name:       "输出被截断时保留完整的题目",
+			content:    `[{"title":"A","answer":"A"},{"title":"B","answer":"B"},{"title":"C","ans`,
+			wantTitles: []string{"A", "B"},
+			wantErrs:   []int{2},
+		},
+		{
+			name:       "代码块内的输出被截断",
+			content:    "```json\n{\"questions\":[{\"title\":\"A\"},{\"title\":\"B",
+			wantTitles: []string{"A"},
+			wantErrs:   []int{1},
+		},
+		{
+			name:       "单道题目字段类型错误",
+			content:    `[{"title":"A"},{"title":123},{"title":"C"}]`,
+			wantTitles: []string{"A", "C"},
+			wantErrs:   []int{1},
+		},
+		{
+			name:       "截断和字段错误按序号排列",
+			content:    `[{"title":1},{"title":"B"},{"title":"C`,
+			wantTitles: []string{"B"},
+			wantErrs:   []int{0, 2},
+		},
+		{
+			name:    "没有JSON内容",
+			content: "抱歉，我无法生成这些题目。",
+			wantErr: true,
+		},
+		{
+			name:    "第一道题目就被截断",
+			content: `[{"title":"A","answ`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			questions, itemErrs, err := extractAIQuestions(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v，期望出错 = %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var titles []string
+			for _, q := range questions {
+				titles = append(titles, q.Title)
+			}
+			if !reflect.DeepEqual(titles, tt.wantTitles) {
+				t.Errorf("题目 = %q，期望 %q", titles, tt.wantTitles)
+			}
+
+			var indexes []int
+			for _, e := range itemErrs {
+				if e.Error == "" {
+					t.Errorf("第%d题的解析错误缺少原因", e.Index)
+				}
+				indexes = append(indexes, e.Index)
+			}
+			if !reflect.DeepEqual(indexes, tt.wantErrs) {
+				t.Errorf("解析失败的题目 = %v，期望 %v（%+v）", indexes, tt.wantErrs, itemErrs)
+			}
+		})
+	}
+}
+
+func TestExtractAIQuestionsItemErrorRaw(t *testing.T) {
+	// 字段错误保留截断后的原始内容，便于排查；截断的题目没有完整原文
+	long := strings.Repeat("长", maxRawLength+10)
+	content := `[{"title":"A"},{"title":["` + long + `"]},{"title":"C`
+	_, itemErrs, err := extractAIQuestions(content)
+	if err != nil {
+		t.Fatalf("extractAIQuestions() err = %v", err)
+	}
+	if len(itemErrs) != 2 {
+		t.Fatalf("解析错误 = %+v，期望2个", itemErrs)
+	}
+	if raw := itemErrs[0].Raw; !strings.HasSuffix(raw, "...") || len([]rune(raw)) != maxRawLength+3 {
+		t.Errorf("第1题原始内容 = %q，期望截断为 %d 个字符", raw, maxRawLength)
+	}
+	if itemErrs[1].Raw != "" {
+		t.Errorf("截断题目的原始内容 = %q，期望为空", itemErrs[1].Raw)
+	}
+}
+
+func TestJSONObjectStreamWrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		want     []string
+		wantOpen bool
+	}{
+		{
+			name:    "数组中的题目对象",
+			content: `[{"title":"A"},{"title":"B"}]`,
+			want:    []string{`{"title":"A"}`, `{"title":"B"}`},
+		},
+		{
+			name:    "嵌套对象和字符串中的括号",
+			content: `[{"title":"a{b}\"[","answer":{"x":[1,{"y":2}]}},{"title":"c\\"}]`,
+			want:    []string{`{"title":"a{b}\"[","answer":{"x":[1,{"y":2}]}}`, `{"title":"c\\"}`},
+		},
+		{
+			name:    "questions字段包裹",
+			content: `{"questions":[{"title":"A"}]}`,
+			want:    []string{`{"title":"A"}`},
+		},
+		{
+			name:    "代码块和说明文字",
+			content: "以下是题目：\n```json\n[{\"title\":\"中文标题\"}]\n```",
+			want:    []string{`{"title":"中文标题"}`},
+		},
+		{
+			name:     "输出被截断",
+			content:  `[{"title":"A"},{"title":"B`,
+			want:     []string{`{"title":"A"}`},
+			wantOpen: true,
+		},
+		{
+			name:    "单个对象不是数组元素",
+			content: `{"title":"A"}`,
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		// 按每种分段长度切分输出，模拟对象跨越多个SSE数据块
+		for size := 1; size <= len(tt.content); size++ {
+			var s jsonObjectStream
+			var got []string
+			for start := 0; start < len(tt.content); start += size {
+				end := min(start+size, len(tt.content))
+				got = append(got, s.Write(tt.content[start:end])...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s（每段%d字节）: 对象 = %q，期望 %q", tt.name, size, got, tt.want)
+			}
+			if s.Open() != tt.wantOpen {
+				t.Errorf("%s（每段%d字节）: Open() = %v，期望 %v", tt.name, size, s.Open(), tt.wantOpen)
+			}
+		}
+	}
+}
+
+func TestErrNoValidQuestions(t *testing.T) {
+	tests := []struct {
+		name     string
+		itemErrs []ItemParseError
+		rejected []RejectedQuestion
+		want     string
+	}{
+		{
+			name:     "校验未通过优先",
+			itemErrs: []ItemParseError{{Index: 0, Error: "字段类型错误"}},
+			rejected: []RejectedQuestion{{Problems: []string{"缺少答案", "选项重复"}}},
+			want:     "AI未生成任何有效题目（1道校验未通过，首个问题：缺少答案；选项重复）",
+		},
+		{
+			name:     "只有解析失败",
+			itemErrs: []ItemParseError{{Index: 1, Error: "字段类型错误"}, {Index: 2, Error: "题目不完整"}},
+			want:     "AI未生成任何有效题目（2道解析失败，首个错误：字段类型错误）",
+		},
+		{
+			name: "没有题目",
+			want: "AI未生成任何题目",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errNoValidQuestions(tt.itemErrs, tt.rejected).Error(); got != tt.want {
+				t.Errorf("errNoValidQuestions() = %q，期望 %q", got, tt.want)
+			}
+		})
+	}
+}
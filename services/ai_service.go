@@ -0,0 +1,15 @@
+package services
+
+import (
+	"CodeQuizAI/ai"
+)
+
+// AvailableAIModels 返回当前已启用的AI模型列表
+func AvailableAIModels() []string {
+	return ai.Models()
+}
+
+// GetAIBreakerStates 返回各AI模型的熔断器状态
+func GetAIBreakerStates() []ai.BreakerStatus {
+	return ai.BreakerStates()
+}
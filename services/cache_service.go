@@ -0,0 +1,155 @@
+package services
+
+import (
+	"CodeQuizAI/ai"
+	"CodeQuizAI/config"
+	"CodeQuizAI/dao"
+	"CodeQuizAI/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"time"
+)
+
+// AI响应缓存：模型、模板版本和提示语都相同的生成请求直接复用缓存的AI原始输出，
+// 复用时仍按本次请求的 preview_id 重新解析、校验并生成临时题目
+
+// responseCache 一次生成请求对应的缓存项
+type responseCache struct {
+	key        string
+	model      string
+	version    string
+	ttl        time.Duration
+	maxEntries int
+}
+
+// newResponseCache 构造生成请求的缓存项，未启用缓存或请求跳过缓存时返回 nil
+func newResponseCache(cfg *config.Config, req GenerateQuestionRequest, model, version, prompt string) *responseCache {
+	if cfg.AICacheTTLSeconds <= 0 || req.NoCache || req.MockFailure != "" {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(model + "\n" + version + "\n" + prompt))
+	return &responseCache{
+		key:        hex.EncodeToString(sum[:]),
+		model:      model,
+		version:    version,
+		ttl:        time.Duration(cfg.AICacheTTLSeconds) * time.Second,
+		maxEntries: cfg.AICacheMaxEntries,
+	}
+}
+
+// get 查询未过期的缓存内容，命中时累加命中次数（查询失败按未命中处理）
+func (rc *responseCache) get(ctx context.Context) (string, bool) {
+	if rc == nil {
+		return "", false
+	}
+	c := dao.AIResponseCache
+	now := time.Now()
+	entry, err := dao.Q.AIResponseCache.WithContext(ctx).Where(c.CacheKey.Eq(rc.key), c.ExpiresAt.Gt(now)).First()
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("警告：查询AI响应缓存失败: %v", err)
+		}
+		return "", false
+	}
+	if _, err := dao.Q.AIResponseCache.WithContext(ctx).
+		Where(c.CacheKey.Eq(rc.key)).
+		UpdateSimple(c.Hits.Add(1), c.LastUsedAt.Value(now)); err != nil {
+		log.Printf("警告：更新AI响应缓存失败: %v", err)
+	}
+	return entry.Content, true
+}
+
+// put 写入缓存（已存在时覆盖）并清理过期和超出容量的缓存项，写入失败只记录日志
+func (rc *responseCache) put(ctx context.Context, content string) {
+	if rc == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	now := time.Now()
+	entry := &models.AIResponseCache{
+		CacheKey:        rc.key,
+		AiModel:         rc.model,
+		TemplateVersion: rc.version,
+		Content:         content,
+		ExpiresAt:       now.Add(rc.ttl),
+		LastUsedAt:      now,
+	}
+	err := dao.Q.AIResponseCache.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "cache_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"content", "expires_at", "last_used_at"}),
+		}).
+		Create(entry)
+	if err != nil {
+		log.Printf("警告：写入AI响应缓存失败: %v", err)
+		return
+	}
+	if err := evictResponseCache(ctx, now, rc.maxEntries); err != nil {
+		log.Printf("警告：清理AI响应缓存失败: %v", err)
+	}
+}
+
+// evictResponseCache 删除过期的缓存项，数量超出上限时删除最久未使用的
+func evictResponseCache(ctx context.Context, now time.Time, maxEntries int) error {
+	c := dao.AIResponseCache
+	if _, err := dao.Q.AIResponseCache.WithContext(ctx).Where(c.ExpiresAt.Lte(now)).Delete(); err != nil {
+		return err
+	}
+	total, err := dao.Q.AIResponseCache.WithContext(ctx).Count()
+	if err != nil || total <= int64(maxEntries) {
+		return err
+	}
+	var keys []string
+	if err := dao.Q.AIResponseCache.WithContext(ctx).
+		Order(c.LastUsedAt).
+		Limit(int(total)-maxEntries).
+		Pluck(c.CacheKey, &keys); err != nil {
+		return err
+	}
+	_, err = dao.Q.AIResponseCache.WithContext(ctx).Where(c.CacheKey.In(keys...)).Delete()
+	return err
+}
+
+// chatCached 优先使用缓存的模型输出（命中时记录一次 token 为0的调用），未命中时调用模型。
+// 输出是否写入缓存由调用方在确认生成出有效题目后决定
+func (c *aiCall) chatCached(ctx context.Context, provider ai.Provider, cache *responseCache, req *ai.Request) (*ai.Response, error) {
+	if cache == nil {
+		return c.chat(ctx, provider, req)
+	}
+	start := time.Now()
+	if content, ok := cache.get(ctx); ok {
+		resp := &ai.Response{Content: content}
+		c.cache = models.AICallCacheHit
+		c.record(ctx, provider.Name(), resp, nil, time.Since(start))
+		return resp, nil
+	}
+	c.cache = models.AICallCacheMiss
+	return c.chat(ctx, provider, req)
+}
+
+// chatStreamCached 流式调用时优先使用缓存的模型输出（命中时一次性回调全部内容）
+func (c *aiCall) chatStreamCached(ctx context.Context, streamer ai.StreamProvider, cache *responseCache, req *ai.Request, onDelta func(delta string)) (*ai.Response, error) {
+	if cache == nil {
+		return c.chatStream(ctx, streamer, req, onDelta)
+	}
+	start := time.Now()
+	if content, ok := cache.get(ctx); ok {
+		resp := &ai.Response{Content: content}
+		c.cache = models.AICallCacheHit
+		onDelta(content)
+		c.record(ctx, streamer.Name(), resp, nil, time.Since(start))
+		return resp, nil
+	}
+	c.cache = models.AICallCacheMiss
+	return c.chatStream(ctx, streamer, req, onDelta)
+}
+
+// cacheHit 最近一次调用是否命中了缓存
+func (c *aiCall) cacheHit() bool {
+	return c.cache == models.AICallCacheHit
+}
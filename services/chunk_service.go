@@ -0,0 +1,193 @@
+package services
+
+import (
+	"CodeQuizAI/config"
+	"CodeQuizAI/models"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// 大批量生成：数量超过每批上限时拆分为多批并发调用AI，
+// 各批次结果去重后合并到同一个 preview_id，失败的批次单独报告
+
+// ChunkFailure 生成失败的批次
+type ChunkFailure struct {
+	Chunk int    `json:"chunk"` // 批次序号（从1开始）
+	Count int    `json:"count"` // 该批次请求的题目数量
+	Error string `json:"error"` // 失败原因
+}
+
+// concurrencyLimiter AI调用的并发限制：全局上限和单用户上限
+type concurrencyLimiter struct {
+	mu      sync.Mutex
+	global  chan struct{}
+	perUser map[int64]*userSlots
+	userCap int
+}
+
+// userSlots 单个用户的并发名额
+type userSlots struct {
+	sem  chan struct{} // 已占用的名额
+	refs int           // 占用或等待名额的调用数（为0时从 perUser 中删除，避免随用户数增长）
+}
+
+var (
+	limiter     *concurrencyLimiter
+	limiterOnce sync.Once
+)
+
+// generationLimiter 返回全局的并发限制（按首次使用时的配置创建，修改配置需重启服务）
+func generationLimiter(cfg *config.Config) *concurrencyLimiter {
+	limiterOnce.Do(func() {
+		limiter = &concurrencyLimiter{
+			global:  make(chan struct{}, cfg.GenerateConcurrency),
+			perUser: make(map[int64]*userSlots),
+			userCap: cfg.GenerateUserConcurrency,
+		}
+	})
+	return limiter
+}
+
+// acquire 等待用户和全局的并发名额，返回释放函数；请求取消时返回错误
+func (l *concurrencyLimiter) acquire(ctx context.Context, userID int64) (func(), error) {
+	// 1. 先占用户名额，避免单个用户的大批量请求占满全局名额
+	l.mu.Lock()
+	user, ok := l.perUser[userID]
+	if !ok {
+		user = &userSlots{sem: make(chan struct{}, l.userCap)}
+		l.perUser[userID] = user
+	}
+	user.refs++
+	l.mu.Unlock()
+
+	select {
+	case user.sem <- struct{}{}:
+	case <-ctx.Done():
+		l.leave(userID, user)
+		return nil, fmt.Errorf("等待生成名额时请求已取消：%w", ctx.Err())
+	}
+
+	// 2. 再占全局名额
+	select {
+	case l.global <- struct{}{}:
+	case <-ctx.Done():
+		<-user.sem
+		l.leave(userID, user)
+		return nil, fmt.Errorf("等待生成名额时请求已取消：%w", ctx.Err())
+	}
+
+	return func() {
+		<-l.global
+		<-user.sem
+		l.leave(userID, user)
+	}, nil
+}
+
+// leave 调用结束（释放名额或放弃等待）后减少用户的引用计数，没有调用时删除该用户的名额
+func (l *concurrencyLimiter) leave(userID int64, user *userSlots) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if user.refs--; user.refs == 0 {
+		delete(l.perUser, userID)
+	}
+}
+
+// chunkSizes 按每批上限拆分题目数量（如 25 道、每批 10 道 -> 10、10、5）
+func chunkSizes(count, chunkSize int) []int {
+	var sizes []int
+	for count > 0 {
+		size := min(count, chunkSize)
+		sizes = append(sizes, size)
+		count -= size
+	}
+	return sizes
+}
+
+// generateInChunks 分批并发生成题目并合并结果（不落库）：
+// 各批次独立进行模型降级，部分批次失败时保留成功批次的题目，全部失败时返回错误
+func generateInChunks(
+	ctx context.Context,
+	previewID string,
+	userID int64,
+	reqs []GenerateQuestionRequest,
+	cfg *config.Config,
+) (*GenerateQuestionsResult, error) {
+	// 1. 并发生成各批次（并发数由全局和单用户上限控制）
+	type chunkResult struct {
+		result *GenerateQuestionsResult
+		err    error
+	}
+	results := make([]chunkResult, len(reqs))
+	var wg sync.WaitGroup
+	for i, chunkReq := range reqs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := generateChunk(ctx, previewID, userID, chunkReq, cfg)
+			results[i] = chunkResult{result: result, err: err}
+		}(i)
+	}
+	wg.Wait()
+
+	// 2. 按批次顺序合并，跨批次去掉标题相同或近似重复的题目
+	merged := &GenerateQuestionsResult{}
+	titles := make(map[string]bool)
+	seen := &similarityIndex{}
+	var firstErr error
+	for i, r := range results {
+		chunk := i + 1
+		if r.result != nil {
+			for _, attempt := range r.result.Attempts {
+				attempt.Chunk = chunk
+				merged.Attempts = append(merged.Attempts, attempt)
+			}
+		}
+		if r.err != nil {
+			log.Printf("第%d/%d批题目生成失败: %v", chunk, len(reqs), r.err)
+			merged.FailedChunks = append(merged.FailedChunks, ChunkFailure{Chunk: chunk, Count: reqs[i].Count, Error: r.err.Error()})
+			if firstErr == nil {
+				firstErr = fmt.Errorf("第%d批：%w", chunk, r.err)
+			}
+			continue
+		}
+
+		if merged.AIModel == "" {
+			merged.AIModel = r.result.AIModel
+		}
+		merged.FallbackUsed = merged.FallbackUsed || r.result.FallbackUsed
+		merged.ParseErrors = append(merged.ParseErrors, r.result.ParseErrors...)
+		merged.Rejected = append(merged.Rejected, r.result.Rejected...)
+		for _, q := range r.result.Questions {
+			if isChunkDuplicate(q, titles, seen, cfg.DuplicateThreshold) {
+				merged.Deduplicated++
+				continue
+			}
+			merged.Questions = append(merged.Questions, q)
+		}
+	}
+	if len(merged.Questions) == 0 {
+		return nil, fmt.Errorf("所有批次均生成失败（共 %d 批），%w", len(reqs), firstErr)
+	}
+
+	// 3. 合并后重新编号临时题ID
+	for i := range merged.Questions {
+		merged.Questions[i].TempID = fmt.Sprintf("%s_%d", previewID, i)
+	}
+	return merged, nil
+}
+
+// isChunkDuplicate 检查题目是否与之前批次已合并的题目重复，不重复时将其加入已合并集合
+func isChunkDuplicate(q models.TempQuestion, titles map[string]bool, seen *similarityIndex, threshold float64) bool {
+	key := titleKey(q.Title)
+	if titles[key] {
+		return true
+	}
+	if best, score := seen.closest(questionText(q.Title, q.CodeSnippet, q.Options)); best != nil && score >= threshold {
+		return true
+	}
+	titles[key] = true
+	seen.add(&models.Question{Title: q.Title, CodeSnippet: q.CodeSnippet, Options: q.Options})
+	return false
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"CodeQuizAI/dao"
+	"CodeQuizAI/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 近似重复检测：题目文本（标题、代码片段和选项内容）切分为字符 shingle，
+// 用 MinHash 签名估计两道题目 shingle 集合的 Jaccard 相似度
+const (
+	shingleSize = 3   // 每个 shingle 的字符数
+	minHashSize = 128 // 签名长度（哈希函数个数）
+	lshBands    = 32  // 扫描题库时 LSH 的分段数（每段 minHashSize/lshBands 个哈希值）
+)
+
+// minHashSeeds 各哈希函数的种子（固定随机源，保证签名稳定）
+var minHashSeeds = func() [minHashSize]uint64 {
+	var seeds [minHashSize]uint64
+	rng := rand.New(rand.NewSource(20240601))
+	for i := range seeds {
+		seeds[i] = rng.Uint64()
+	}
+	return seeds
+}()
+
+// minHashSignature 题目文本的 MinHash 签名
+type minHashSignature [minHashSize]uint64
+
+// mix64 64位整数的混淆函数（splitmix64），由 shingle 哈希派生出各哈希函数的取值
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// questionText 参与比对的题目文本：标题、代码片段和选项内容（去掉 "A. " 等标签）
+func questionText(title, codeSnippet, options string) string {
+	parts := []string{title, codeSnippet}
+	var optionList []string
+	if json.Unmarshal([]byte(options), &optionList) == nil {
+		for _, option := range optionList {
+			if m := optionLabelPattern.FindStringSubmatch(strings.TrimSpace(option)); m != nil {
+				option = m[2]
+			}
+			parts = append(parts, option)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// newMinHashSignature 计算文本的签名：忽略大小写、空白和标点，文本为空时返回 false
+func newMinHashSignature(text string) (minHashSignature, bool) {
+	var sig minHashSignature
+	var runes []rune
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, r)
+		}
+	}
+	if len(runes) == 0 {
+		return sig, false
+	}
+
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	count := max(len(runes)-shingleSize+1, 1)
+	for start := 0; start < count; start++ {
+		end := min(start+shingleSize, len(runes))
+		h := fnv.New64a()
+		h.Write([]byte(string(runes[start:end])))
+		shingle := h.Sum64()
+		for i, seed := range minHashSeeds {
+			if v := mix64(shingle ^ seed); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig, true
+}
+
+// similarity 估计两个签名对应文本的 Jaccard 相似度（保留3位小数）
+func (s *minHashSignature) similarity(other *minHashSignature) float64 {
+	same := 0
+	for i := range s {
+		if s[i] == other[i] {
+			same++
+		}
+	}
+	return math.Round(float64(same)/minHashSize*1000) / 1000
+}
+
+// bankQuestion 题库中参与比对的题目
+type bankQuestion struct {
+	question *models.Question
+	sig      minHashSignature
+}
+
+// similarityIndex 用户题库的签名索引
+type similarityIndex struct {
+	entries []bankQuestion
+}
+
+// loadSimilarityIndex 加载用户题库中的题目并计算签名（language 为空时不限语言）
+func loadSimilarityIndex(ctx context.Context, userID int64, language string) (*similarityIndex, error) {
+	query := dao.Q.Question.WithContext(ctx).
+		Select(dao.Question.ID, dao.Question.Title, dao.Question.QuestionType, dao.Question.Options,
+			dao.Question.CodeSnippet, dao.Question.Language, dao.Question.CreatedAt).
+		Where(dao.Question.UserID.Eq(userID))
+	if language != "" {
+		query = query.Where(dao.Question.Language.Eq(language))
+	}
+	questions, err := query.Order(dao.Question.ID).Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询题库失败：%w", err)
+	}
+
+	index := &similarityIndex{}
+	for _, q := range questions {
+		if sig, ok := newMinHashSignature(questionText(q.Title, q.CodeSnippet, q.Options)); ok {
+			index.entries = append(index.entries, bankQuestion{question: q, sig: sig})
+		}
+	}
+	return index, nil
+}
+
+// closest 查找与给定文本最相似的题目，题库为空时返回 nil
+func (idx *similarityIndex) closest(text string) (*models.Question, float64) {
+	sig, ok := newMinHashSignature(text)
+	if !ok {
+		return nil, 0
+	}
+	var best *models.Question
+	bestScore := -1.0
+	for i := range idx.entries {
+		if score := idx.entries[i].sig.similarity(&sig); score > bestScore {
+			best, bestScore = idx.entries[i].question, score
+		}
+	}
+	if best == nil {
+		return nil, 0
+	}
+	return best, bestScore
+}
+
+// add 将题目加入索引（同一批次确认的题目之间也要互相比对）
+func (idx *similarityIndex) add(q *models.Question) {
+	if sig, ok := newMinHashSignature(questionText(q.Title, q.CodeSnippet, q.Options)); ok {
+		idx.entries = append(idx.entries, bankQuestion{question: q, sig: sig})
+	}
+}
+
+// markSimilar 为临时题目标记题库中最相似的题目及相似度
+func (idx *similarityIndex) markSimilar(temp *models.TempQuestion) {
+	best, score := idx.closest(questionText(temp.Title, temp.CodeSnippet, temp.Options))
+	if best == nil {
+		return
+	}
+	id := best.ID
+	temp.SimilarQuestionID = &id
+	temp.Similarity = score
+}
+
+// DuplicateMatch 与题库中已有题目重复的临时题目
+type DuplicateMatch struct {
+	TempID            string  `json:"temp_id"`             // 临时题ID
+	SimilarQuestionID int64   `json:"similar_question_id"` // 最相似的正式题目ID
+	Similarity        float64 `json:"similarity"`          // 相似度
+}
+
+// FindDuplicatesRequest 扫描题库重复题目的参数
+type FindDuplicatesRequest struct {
+	Language  string  `form:"language"`                                 // 编程语言（可选）
+	Threshold float64 `form:"threshold" binding:"omitempty,gt=0,lte=1"` // 相似度阈值（可选，默认使用服务端配置）
+}
+
+// DuplicateCluster 一组互相近似重复的题目
+type DuplicateCluster struct {
+	Questions     []DuplicateQuestion `json:"questions"`      // 组内题目（按ID排序）
+	Pairs         []DuplicatePair     `json:"pairs"`          // 达到阈值的题目对
+	MaxSimilarity float64             `json:"max_similarity"` // 组内最高相似度
+}
+
+// DuplicateQuestion 重复组中的题目
+type DuplicateQuestion struct {
+	ID           int64     `json:"id"`
+	Title        string    `json:"title"`
+	QuestionType string    `json:"question_type"`
+	Language     string    `json:"language"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// DuplicatePair 两道近似重复的题目
+type DuplicatePair struct {
+	QuestionID int64   `json:"question_id"`
+	OtherID    int64   `json:"other_id"`
+	Similarity float64 `json:"similarity"`
+}
+
+// FindDuplicatesResponse 题库重复扫描结果
+type FindDuplicatesResponse struct {
+	Threshold float64            `json:"threshold"` // 使用的相似度阈值
+	Scanned   int                `json:"scanned"`   // 扫描的题目数量
+	Clusters  []DuplicateCluster `json:"clusters"`  // 重复题目组（按题目数量从多到少）
+}
+
+// FindDuplicateClusters 扫描用户题库中的近似重复题目：
+// 按 LSH 分段找出候选题目对，相似度达到阈值的题目对用并查集合并为重复组
+func FindDuplicateClusters(ctx context.Context, userID int64, language string, threshold float64) (*FindDuplicatesResponse, error) {
+	// 1. 加载题库签名
+	index, err := loadSimilarityIndex(ctx, userID, language)
+	if err != nil {
+		return nil, err
+	}
+	entries := index.entries
+
+	// 2. LSH：任意一段哈希值完全相同的题目成为候选对
+	rows := minHashSize / lshBands
+	candidates := make(map[[2]int]bool)
+	for band := 0; band < lshBands; band++ {
+		buckets := make(map[string][]int)
+		for i := range entries {
+			key := fmt.Sprint(entries[i].sig[band*rows : (band+1)*rows])
+			buckets[key] = append(buckets[key], i)
+		}
+		for _, members := range buckets {
+			for a := 0; a < len(members); a++ {
+				for b := a + 1; b < len(members); b++ {
+					candidates[[2]int{members[a], members[b]}] = true
+				}
+			}
+		}
+	}
+
+	// 3. 校验候选对的相似度，达到阈值的合并到同一组
+	parent := make([]int, len(entries))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	var pairs [][2]int
+	scores := make(map[[2]int]float64)
+	for pair := range candidates {
+		score := entries[pair[0]].sig.similarity(&entries[pair[1]].sig)
+		if score < threshold {
+			continue
+		}
+		pairs = append(pairs, pair)
+		scores[pair] = score
+		parent[find(pair[0])] = find(pair[1])
+	}
+
+	// 4. 组装重复组
+	groups := make(map[int]*DuplicateCluster)
+	sort.Slice(pairs, func(a, b int) bool {
+		if pairs[a][0] != pairs[b][0] {
+			return pairs[a][0] < pairs[b][0]
+		}
+		return pairs[a][1] < pairs[b][1]
+	})
+	for _, pair := range pairs {
+		root := find(pair[0])
+		cluster, ok := groups[root]
+		if !ok {
+			cluster = &DuplicateCluster{}
+			groups[root] = cluster
+		}
+		cluster.Pairs = append(cluster.Pairs, DuplicatePair{
+			QuestionID: entries[pair[0]].question.ID,
+			OtherID:    entries[pair[1]].question.ID,
+			Similarity: scores[pair],
+		})
+		cluster.MaxSimilarity = max(cluster.MaxSimilarity, scores[pair])
+	}
+	for i := range entries {
+		if cluster, ok := groups[find(i)]; ok {
+			q := entries[i].question
+			cluster.Questions = append(cluster.Questions, DuplicateQuestion{
+				ID:           q.ID,
+				Title:        q.Title,
+				QuestionType: q.QuestionType,
+				Language:     q.Language,
+				CreatedAt:    q.CreatedAt,
+			})
+		}
+	}
+
+	clusters := make([]DuplicateCluster, 0, len(groups))
+	for _, cluster := range groups {
+		clusters = append(clusters, *cluster)
+	}
+	sort.Slice(clusters, func(a, b int) bool {
+		if len(clusters[a].Questions) != len(clusters[b].Questions) {
+			return len(clusters[a].Questions) > len(clusters[b].Questions)
+		}
+		return clusters[a].Questions[0].ID < clusters[b].Questions[0].ID
+	})
+
+	return &FindDuplicatesResponse{
+		Threshold: threshold,
+		Scanned:   len(entries),
+		Clusters:  clusters,
+	}, nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"CodeQuizAI/dao"
+	"CodeQuizAI/models"
+	"CodeQuizAI/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// idempotencyLockTimeout 幂等键处理中状态的超时时间（超时视为处理中断，允许重新处理）
+const idempotencyLockTimeout = 5 * time.Minute
+
+// IdempotentResponse 幂等键对应的首次请求的响应
+type IdempotentResponse struct {
+	Code    int
+	Message string
+	Data    json.RawMessage
+}
+
+// idempotencyRequestHash 接口名称和请求参数的 SHA-256
+func idempotencyRequestHash(endpoint string, params interface{}) (string, error) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("序列化请求参数失败：%w", err)
+	}
+	sum := sha256.Sum256(append([]byte(endpoint+"\n"), body...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// BeginIdempotentRequest 占用幂等键：键未使用时记录为处理中并返回 nil，调用方处理完成后需调用
+// CompleteIdempotentRequest 或 ReleaseIdempotentRequest；同一请求已处理完成时返回保存的响应；
+// 键正在被其他请求处理时返回 utils.ErrIdempotencyKeyInUse，已用于不同的请求时返回 utils.ErrIdempotencyKeyMismatch
+func BeginIdempotentRequest(ctx context.Context, userID int64, key, endpoint string, params interface{}) (*IdempotentResponse, error) {
+	hash, err := idempotencyRequestHash(endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 尝试占用幂等键（主键冲突说明键已被使用）
+	q := dao.Q.IdempotencyKey
+	createErr := q.WithContext(ctx).Create(&models.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: hash,
+		Status:      models.IdempotencyStatusProcessing,
+	})
+	if createErr == nil {
+		return nil, nil
+	}
+	existing, err := q.WithContext(ctx).Where(q.UserID.Eq(userID), q.Key.Eq(key)).First()
+	if err != nil {
+		return nil, fmt.Errorf("保存幂等键失败：%w", createErr)
+	}
+
+	// 2. 键已被使用：校验请求是否相同
+	if existing.RequestHash != hash {
+		return nil, utils.ErrIdempotencyKeyMismatch
+	}
+	if existing.Status == models.IdempotencyStatusCompleted {
+		return &IdempotentResponse{
+			Code:    existing.ResponseCode,
+			Message: existing.ResponseMessage,
+			Data:    json.RawMessage(existing.ResponseData),
+		}, nil
+	}
+
+	// 3. 处理中：超时未完成的（如服务中途重启）允许重新处理
+	info, err := q.WithContext(ctx).
+		Where(q.UserID.Eq(userID), q.Key.Eq(key), q.Status.Eq(models.IdempotencyStatusProcessing), q.UpdatedAt.Lt(time.Now().Add(-idempotencyLockTimeout))).
+		Update(q.UpdatedAt, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("更新幂等键失败：%w", err)
+	}
+	if info.RowsAffected == 0 {
+		return nil, utils.ErrIdempotencyKeyInUse
+	}
+	return nil, nil
+}
+
+// CompleteIdempotentRequest 保存首次请求的响应（保存失败只记录日志，不影响本次响应）
+func CompleteIdempotentRequest(ctx context.Context, userID int64, key string, code int, message string, data interface{}) {
+	body, err := json.Marshal(data)
+	if err == nil {
+		q := dao.Q.IdempotencyKey
+		_, err = q.WithContext(context.WithoutCancel(ctx)).
+			Where(q.UserID.Eq(userID), q.Key.Eq(key)).
+			UpdateSimple(
+				q.Status.Value(models.IdempotencyStatusCompleted),
+				q.ResponseCode.Value(code),
+				q.ResponseMessage.Value(message),
+				q.ResponseData.Value(string(body)),
+				q.UpdatedAt.Value(time.Now()),
+			)
+	}
+	if err != nil {
+		log.Printf("警告：保存幂等键响应失败，user_id=%d, key=%s, err=%v", userID, key, err)
+	}
+}
+
+// ReleaseIdempotentRequest 请求失败时释放幂等键，客户端可以用同一个键重试
+func ReleaseIdempotentRequest(ctx context.Context, userID int64, key string) {
+	q := dao.Q.IdempotencyKey
+	if _, err := q.WithContext(context.WithoutCancel(ctx)).Where(q.UserID.Eq(userID), q.Key.Eq(key)).Delete(); err != nil {
+		log.Printf("警告：释放幂等键失败，user_id=%d, key=%s, err=%v", userID, key, err)
+	}
+}
+
+// purgeIdempotencyKeys 删除 cutoff 之前最后更新的幂等键。按更新时间而不是创建时间判断：
+// 处理中超时后被重新占用的键创建时间较早，但仍在处理中，不能删除
+func purgeIdempotencyKeys(ctx context.Context, cutoff time.Time) error {
+	q := dao.Q.IdempotencyKey
+	if _, err := q.WithContext(ctx).Where(q.UpdatedAt.Lt(cutoff)).Delete(); err != nil {
+		return fmt.Errorf("删除过期幂等键失败：%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"CodeQuizAI/config"
+	"CodeQuizAI/dao"
+	"CodeQuizAI/models"
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"log"
+	"time"
+)
+
+// purgeBatchSize 每批过期或彻底删除的临时题目数量
+const purgeBatchSize = 500
+
+// StartPreviewJanitor 启动过期预览的清理任务：服务启动时执行一次，之后按配置的间隔定期执行
+func StartPreviewJanitor(cfg *config.Config) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(cfg.JanitorIntervalMinutes) * time.Minute)
+		defer ticker.Stop()
+		for {
+			runPreviewJanitor(context.Background(), cfg)
+			<-ticker.C
+		}
+	}()
+}
+
+// runPreviewJanitor 执行一次清理：软删除超过有效期仍未确认的临时题目，
+// 彻底删除软删除（已确认、已丢弃或已过期）超过保留期的临时题目及其历史版本，
+// 同时删除超过保留期的幂等键和未结算的配额预占，执行结果写入 janitor_runs 表
+func runPreviewJanitor(ctx context.Context, cfg *config.Config) *models.JanitorRun {
+	run := &models.JanitorRun{StartedAt: time.Now()}
+
+	// 1. 过期：软删除超过有效期的未确认题目
+	expired, err := expireTempQuestions(ctx, run.StartedAt.Add(-time.Duration(cfg.PreviewTTLHours)*time.Hour), run.StartedAt)
+	run.Expired = expired
+
+	// 2. 清理：彻底删除软删除超过保留期的题目和幂等键，以及已过期或任务已结束的配额预占
+	retentionCutoff := run.StartedAt.Add(-time.Duration(cfg.PreviewRetentionHours) * time.Hour)
+	if err == nil {
+		run.Purged, err = purgeTempQuestions(ctx, retentionCutoff)
+	}
+	if err == nil {
+		err = purgeIdempotencyKeys(ctx, retentionCutoff)
+	}
+	if err == nil {
+		err = purgeQuotaReservations(ctx, run.StartedAt)
+	}
+
+	// 3. 记录执行结果
+	run.DurationMs = time.Since(run.StartedAt).Milliseconds()
+	if err != nil {
+		run.Error = err.Error()
+		log.Printf("预览清理失败（已过期 %d 道，已删除 %d 道）: %v", run.Expired, run.Purged, err)
+	} else {
+		log.Printf("预览清理完成：过期 %d 道，彻底删除 %d 道，耗时 %dms", run.Expired, run.Purged, run.DurationMs)
+	}
+	if err := dao.Q.JanitorRun.WithContext(ctx).Create(run); err != nil {
+		log.Printf("警告：保存预览清理记录失败: %v", err)
+	}
+	return run
+}
+
+// expireTempQuestions 分批软删除 cutoff 之前生成且仍未确认的临时题目，并标记过期时间
+func expireTempQuestions(ctx context.Context, cutoff, now time.Time) (int64, error) {
+	t := dao.TempQuestion
+	var expired int64
+	for {
+		var ids []int64
+		if err := dao.Q.TempQuestion.WithContext(ctx).
+			Where(t.CreatedAt.Lt(cutoff)).
+			Limit(purgeBatchSize).
+			Pluck(t.ID, &ids); err != nil {
+			return expired, fmt.Errorf("查询过期预览失败：%w", err)
+		}
+		if len(ids) == 0 {
+			return expired, nil
+		}
+
+		info, err := dao.Q.TempQuestion.WithContext(ctx).
+			Where(t.ID.In(ids...)).
+			UpdateSimple(t.ExpiredAt.Value(now), t.DeletedAt.Value(gorm.DeletedAt{Time: now, Valid: true}))
+		if err != nil {
+			return expired, fmt.Errorf("标记过期预览失败：%w", err)
+		}
+		expired += info.RowsAffected
+		if len(ids) < purgeBatchSize {
+			return expired, nil
+		}
+	}
+}
+
+// purgeTempQuestions 分批彻底删除 cutoff 之前软删除的临时题目及其历史版本
+func purgeTempQuestions(ctx context.Context, cutoff time.Time) (int64, error) {
+	t := dao.TempQuestion
+	var purged int64
+	for {
+		var ids []int64
+		if err := dao.Q.TempQuestion.WithContext(ctx).Unscoped().
+			Where(t.DeletedAt.Lt(gorm.DeletedAt{Time: cutoff, Valid: true})).
+			Limit(purgeBatchSize).
+			Pluck(t.ID, &ids); err != nil {
+			return purged, fmt.Errorf("查询待删除的临时题目失败：%w", err)
+		}
+		if len(ids) == 0 {
+			return purged, nil
+		}
+
+		if err := deleteTempQuestionRevisions(ctx, ids); err != nil {
+			return purged, fmt.Errorf("删除临时题目历史版本失败：%w", err)
+		}
+		info, err := dao.Q.TempQuestion.WithContext(ctx).Unscoped().Where(t.ID.In(ids...)).Delete()
+		if err != nil {
+			return purged, fmt.Errorf("删除临时题目失败：%w", err)
+		}
+		purged += info.RowsAffected
+		if len(ids) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// JanitorStatus 预览清理任务的运行情况（管理员统计中展示）
+type JanitorStatus struct {
+	PendingQuestions int64                `json:"pending_questions"` // 当前未确认的临时题目数量
+	TotalExpired     int64                `json:"total_expired"`     // 累计过期的临时题目数量
+	TotalPurged      int64                `json:"total_purged"`      // 累计彻底删除的临时题目数量
+	LastRun          *models.JanitorRun   `json:"last_run"`          // 最近一次执行（尚未执行时为空）
+	RecentRuns       []*models.JanitorRun `json:"recent_runs"`       // 最近10次执行（从新到旧）
+}
+
+// getJanitorStatus 汇总预览清理任务的执行记录
+func getJanitorStatus(ctx context.Context) (*JanitorStatus, error) {
+	status := &JanitorStatus{}
+	var err error
+
+	// 1. 当前未确认的临时题目数量
+	status.PendingQuestions, err = dao.Q.TempQuestion.WithContext(ctx).Count()
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 累计过期和删除的数量
+	j := dao.JanitorRun
+	var totals struct {
+		Expired int64
+		Purged  int64
+	}
+	if err := dao.Q.JanitorRun.WithContext(ctx).
+		Select(j.Expired.Sum().As("expired"), j.Purged.Sum().As("purged")).
+		Scan(&totals); err != nil {
+		return nil, err
+	}
+	status.TotalExpired, status.TotalPurged = totals.Expired, totals.Purged
+
+	// 3. 最近的执行记录
+	status.RecentRuns, err = dao.Q.JanitorRun.WithContext(ctx).Order(j.ID.Desc()).Limit(10).Find()
+	if err != nil {
+		return nil, err
+	}
+	if len(status.RecentRuns) > 0 {
+		status.LastRun = status.RecentRuns[0]
+	}
+	return status, nil
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"CodeQuizAI/config"
+	"CodeQuizAI/dao"
+	"CodeQuizAI/models"
+	"CodeQuizAI/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"log"
+	"time"
+)
+
+// jobQueue 待执行的任务ID队列（由 StartJobWorkers 初始化）
+var jobQueue chan int64
+
+// jobConfig 工作协程使用的配置
+var jobConfig *config.Config
+
+// StartJobWorkers 启动异步生成任务的工作协程，并恢复重启前未完成的任务
+func StartJobWorkers(cfg *config.Config) error {
+	jobConfig = cfg
+	jobQueue = make(chan int64, cfg.JobQueueSize)
+
+	for i := 0; i < cfg.JobWorkers; i++ {
+		go runJobWorker()
+	}
+
+	// 恢复排队中和执行中（重启时被中断）的任务
+	unfinished, err := dao.Q.GenerationJob.WithContext(context.Background()).
+		Where(dao.GenerationJob.Status.In(models.JobStatusQueued, models.JobStatusRunning)).
+		Order(dao.GenerationJob.ID.Asc()).
+		Find()
+	if err != nil {
+		return fmt.Errorf("查询未完成的生成任务失败: %w", err)
+	}
+	for _, job := range unfinished {
+		if err := enqueueJob(job.ID); err != nil {
+			failJob(job.ID, "服务重启后任务队列已满，任务未能恢复")
+			releaseJobReservation(job.ID)
+		}
+	}
+	if len(unfinished) > 0 {
+		log.Printf("已恢复 %d 个未完成的生成任务", len(unfinished))
+	}
+	return nil
+}
+
+// enqueueJob 将任务放入队列（队列已满时立即返回错误，不阻塞）
+func enqueueJob(jobID int64) error {
+	select {
+	case jobQueue <- jobID:
+		return nil
+	default:
+		return utils.ErrJobQueueFull
+	}
+}
+
+// SubmitGenerationJob 提交异步生成任务。任务持有提交前预占的配额，在任务结束（或提交失败）时结算
+func SubmitGenerationJob(
+	ctx context.Context,
+	previewID string,
+	userID int64,
+	req GenerateQuestionRequest,
+	reservation *QuotaReservation,
+) (*models.GenerationJob, error) {
+	// 1. 记录任务（请求参数以JSON保存，重启后可重新执行）
+	reqJSON, err := json.Marshal(req)
+	if err != nil {
+		reservation.Release()
+		return nil, fmt.Errorf("序列化请求参数失败：%w", err)
+	}
+	job := &models.GenerationJob{
+		UserID:    userID,
+		Status:    models.JobStatusQueued,
+		Request:   string(reqJSON),
+		PreviewID: previewID,
+	}
+	if err := dao.Q.GenerationJob.WithContext(ctx).Create(job); err != nil {
+		reservation.Release()
+		return nil, fmt.Errorf("创建生成任务失败：%w", err)
+	}
+
+	// 2. 预占转交给任务（先关联再入队，工作协程可能在入队后立即执行完任务；重启后恢复的任务仍持有预占）
+	if err := reservation.attachJob(ctx, job.ID); err != nil {
+		reservation.Release()
+		failJob(job.ID, err.Error())
+		return nil, err
+	}
+
+	// 3. 放入队列
+	if err := enqueueJob(job.ID); err != nil {
+		failJob(job.ID, err.Error())
+		releaseJobReservation(job.ID)
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// runJobWorker 工作协程：逐个执行队列中的任务
+func runJobWorker() {
+	for jobID := range jobQueue {
+		runJob(jobID)
+	}
+}
+
+// runJob 执行单个生成任务
+func runJob(jobID int64) {
+	// 1. 任务使用独立的上下文（与提交任务的HTTP请求无关），并限制最长执行时间；结束时结算预占的配额
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(jobConfig.JobTimeoutSeconds)*time.Second)
+	defer cancel()
+	defer releaseJobReservation(jobID)
+
+	job, err := dao.Q.GenerationJob.WithContext(ctx).Where(dao.GenerationJob.ID.Eq(jobID)).First()
+	if err != nil {
+		log.Printf("警告：读取生成任务失败，job_id=%d, err=%v", jobID, err)
+		return
+	}
+
+	// 2. 标记为执行中
+	now := time.Now()
+	if _, err := dao.Q.GenerationJob.WithContext(ctx).
+		Where(dao.GenerationJob.ID.Eq(jobID)).
+		Updates(map[string]interface{}{"status": models.JobStatusRunning, "started_at": now}); err != nil {
+		log.Printf("警告：更新生成任务状态失败，job_id=%d, err=%v", jobID, err)
+	}
+
+	// 3. 解析请求参数
+	var req GenerateQuestionRequest
+	if err := json.Unmarshal([]byte(job.Request), &req); err != nil {
+		failJob(jobID, "请求参数解析失败："+err.Error())
+		return
+	}
+
+	// 4. 重启前已保存过题目的任务不再重复生成：无法确认是否全部生成且生成报告已丢失，标记为部分成功
+	saved, err := dao.Q.TempQuestion.WithContext(ctx).
+		Where(
+			dao.TempQuestion.PreviewID.Eq(job.PreviewID),
+			dao.TempQuestion.UserID.Eq(job.UserID),
+		).
+		Count()
+	if err == nil && saved > 0 {
+		finishJob(jobID, models.JobStatusPartial, fmt.Sprintf("服务重启时任务被中断，已保存 %d 道题目（请求 %d 道），生成报告未能保留", saved, req.Count), nil)
+		return
+	}
+
+	// 5. 执行生成
+	result, err := GenerateQuestions(ctx, job.PreviewID, job.UserID, req, jobConfig)
+	if err != nil {
+		failJob(jobID, err.Error())
+		return
+	}
+
+	// 6. 记录生成结果（部分批次失败时标记为部分成功）
+	if len(result.FailedChunks) > 0 {
+		finishJob(jobID, models.JobStatusPartial, fmt.Sprintf("题目部分生成成功（%d 批失败）", len(result.FailedChunks)), result)
+		return
+	}
+	finishJob(jobID, models.JobStatusSucceeded, "", result)
+}
+
+// finishJob 将任务标记为成功或部分成功，并记录生成结果（结果未知时为 nil）和部分成功的原因
+func finishJob(jobID int64, status, reason string, result *GenerateQuestionsResult) {
+	updates := map[string]interface{}{
+		"status":      status,
+		"error":       reason,
+		"finished_at": time.Now(),
+	}
+	if result != nil {
+		attemptsJSON, _ := json.Marshal(result.Attempts)
+		updates["ai_model"] = result.AIModel
+		updates["fallback_used"] = result.FallbackUsed
+		updates["attempts"] = string(attemptsJSON)
+		if resultJSON, err := json.Marshal(result); err == nil {
+			updates["result"] = string(resultJSON)
+		}
+	}
+
+	if _, err := dao.Q.GenerationJob.WithContext(context.Background()).
+		Where(dao.GenerationJob.ID.Eq(jobID)).
+		Updates(updates); err != nil {
+		log.Printf("警告：更新生成任务结果失败，job_id=%d, err=%v", jobID, err)
+	}
+}
+
+// failJob 将任务标记为失败
+func failJob(jobID int64, reason string) {
+	if _, err := dao.Q.GenerationJob.WithContext(context.Background()).
+		Where(dao.GenerationJob.ID.Eq(jobID)).
+		Updates(map[string]interface{}{
+			"status":      models.JobStatusFailed,
+			"error":       reason,
+			"finished_at": time.Now(),
+		}); err != nil {
+		log.Printf("警告：更新生成任务状态失败，job_id=%d, err=%v", jobID, err)
+	}
+}
+
+// GenerationJobResponse 生成任务状态响应
+type GenerationJobResponse struct {
+	ID           int64                 `json:"id"`                      // 任务ID
+	Status       string                `json:"status"`                  // 任务状态
+	PreviewID    string                `json:"preview_id,omitempty"`    // 预览批次ID（成功后返回）
+	AIModel      string                `json:"ai_model,omitempty"`      // 实际生成题目的模型
+	FallbackUsed bool                  `json:"fallback_used"`           // 是否发生了模型降级
+	Attempts     []ModelAttempt        `json:"attempts,omitempty"`      // 各模型的尝试记录
+	Questions    []models.TempQuestion `json:"questions,omitempty"`     // 生成的临时题目（成功后返回）
+	ParseErrors  []ItemParseError      `json:"parse_errors,omitempty"`  // 解析失败被跳过的题目
+	Rejected     []RejectedQuestion    `json:"rejected,omitempty"`      // 校验未通过（修正后仍不合格）的题目
+	FailedChunks []ChunkFailure        `json:"failed_chunks,omitempty"` // 生成失败的批次（分批生成时）
+	Deduplicated int                   `json:"deduplicated,omitempty"`  // 与其他批次重复而被去掉的题目数量
+	Verification *VerificationSummary  `json:"verification,omitempty"`  // 答案校验结果（开启校验时）
+	Error        string                `json:"error,omitempty"`         // 失败原因（部分成功时为未全部生成的原因）
+	CreatedAt    time.Time             `json:"created_at"`              // 提交时间
+	StartedAt    *time.Time            `json:"started_at,omitempty"`    // 开始执行时间
+	FinishedAt   *time.Time            `json:"finished_at,omitempty"`   // 结束时间
+}
+
+// GetGenerationJob 查询生成任务状态（仅允许查询自己的任务）
+func GetGenerationJob(ctx context.Context, jobID, userID int64) (GenerationJobResponse, error) {
+	// 1. 查询任务
+	job, err := dao.Q.GenerationJob.WithContext(ctx).
+		Where(
+			dao.GenerationJob.ID.Eq(jobID),
+			dao.GenerationJob.UserID.Eq(userID),
+		).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return GenerationJobResponse{}, utils.ErrJobNotFound
+		}
+		return GenerationJobResponse{}, fmt.Errorf("查询生成任务失败：%w", err)
+	}
+
+	resp := GenerationJobResponse{
+		ID:           job.ID,
+		Status:       job.Status,
+		AIModel:      job.AiModel,
+		FallbackUsed: job.FallbackUsed,
+		Error:        job.Error,
+		CreatedAt:    job.CreatedAt,
+		StartedAt:    job.StartedAt,
+		FinishedAt:   job.FinishedAt,
+	}
+	if job.Attempts != "" {
+		_ = json.Unmarshal([]byte(job.Attempts), &resp.Attempts)
+	}
+	if job.Result != "" {
+		var result GenerateQuestionsResult
+		if err := json.Unmarshal([]byte(job.Result), &result); err == nil {
+			resp.ParseErrors = result.ParseErrors
+			resp.Rejected = result.Rejected
+			resp.FailedChunks = result.FailedChunks
+			resp.Deduplicated = result.Deduplicated
+			resp.Verification = result.Verification
+		}
+	}
+
+	// 2. 成功和部分成功的任务返回预览批次和临时题目
+	if job.Status == models.JobStatusSucceeded || job.Status == models.JobStatusPartial {
+		tempQuestions, err := dao.Q.TempQuestion.WithContext(ctx).
+			Where(
+				dao.TempQuestion.PreviewID.Eq(job.PreviewID),
+				dao.TempQuestion.UserID.Eq(userID),
+			).
+			Order(dao.TempQuestion.ID.Asc()).
+			Find()
+		if err != nil {
+			return GenerationJobResponse{}, fmt.Errorf("查询临时题目失败：%w", err)
+		}
+		resp.PreviewID = job.PreviewID
+		resp.Questions = make([]models.TempQuestion, len(tempQuestions))
+		for i, q := range tempQuestions {
+			resp.Questions[i] = *q
+		}
+	}
+
+	return resp, nil
+}
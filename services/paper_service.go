@@ -5,6 +5,7 @@ import (
 	"CodeQuizAI/models"
 	"CodeQuizAI/utils"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
@@ -177,18 +178,65 @@ type PaperQuestionWithInfo struct {
 
 // QuestionDTO 题目详情DTO
 type QuestionDTO struct {
-	ID           int64     `json:"id"`                    // 题目ID
-	Title        string    `json:"title"`                 // 题目标题
-	QuestionType string    `json:"question_type"`         // 题型（single/multiple）
-	Options      string    `json:"options"`               // 选项（JSON格式字符串）
-	Answer       string    `json:"answer"`                // 答案
-	Explanation  string    `json:"explanation,omitempty"` // 解析（可选）
-	Keywords     string    `json:"keywords,omitempty"`    // 关键词（可选）
-	Language     string    `json:"language"`              // 编程语言
-	AiModel      string    `json:"ai_model"`              // 使用的AI模型
-	UserID       int64     `json:"user_id"`               // 创建者ID
-	CreatedAt    time.Time `json:"created_at"`            // 创建时间
-	UpdatedAt    time.Time `json:"updated_at"`            // 更新时间
+	ID                 int64        `json:"id"`                          // 题目ID
+	Title              string       `json:"title"`                       // 题目标题
+	CodeSnippet        string       `json:"code_snippet,omitempty"`      // 代码片段（可选）
+	CodeLanguage       string       `json:"code_language,omitempty"`     // 代码片段的语言标记
+	QuestionType       string       `json:"question_type"`               // 题型
+	TypeName           string       `json:"type_name"`                   // 题型中文名称
+	Options            string       `json:"options"`                     // 选项（JSON格式字符串，非选择题为 []）
+	Answer             string       `json:"answer"`                      // 答案（存储格式）
+	AcceptedAnswers    []string     `json:"accepted_answers,omitempty"`  // 填空题可接受的答案（re: 开头的按正则匹配）
+	ShortAnswer        *ShortAnswer `json:"short_answer,omitempty"`      // 简答题参考答案和评分要点
+	Explanation        string       `json:"explanation,omitempty"`       // 解析（可选）
+	Keywords           string       `json:"keywords,omitempty"`          // 关键词（可选）
+	Language           string       `json:"language"`                    // 编程语言
+	AiModel            string       `json:"ai_model"`                    // 使用的AI模型
+	Difficulty         string       `json:"difficulty,omitempty"`        // 难度（可选）
+	VerificationStatus string       `json:"verification_status"`         // 答案校验状态
+	SourcePath         string       `json:"source_path,omitempty"`       // 出处的文件路径或资料名称（按资料出题时）
+	SourceStartLine    int          `json:"source_start_line,omitempty"` // 出处的起始行号
+	SourceEndLine      int          `json:"source_end_line,omitempty"`   // 出处的结束行号
+	UserID             int64        `json:"user_id"`                     // 创建者ID
+	CreatedAt          time.Time    `json:"created_at"`                  // 创建时间
+	UpdatedAt          time.Time    `json:"updated_at"`                  // 更新时间
+}
+
+// newQuestionDTO 将题目转换为展示结构，填空题和简答题的答案解析为结构化字段
+func newQuestionDTO(question *models.Question) QuestionDTO {
+	dto := QuestionDTO{
+		ID:           question.ID,
+		Title:        question.Title,
+		CodeSnippet:  question.CodeSnippet,
+		CodeLanguage: question.CodeLanguage,
+		QuestionType: question.QuestionType,
+		TypeName:     questionTypeName(question.QuestionType),
+		Options:      question.Options,
+		Answer:       question.Answer,
+		Explanation:  question.Explanation,
+		Keywords:     question.Keywords,
+		Language:     question.Language,
+		AiModel:      question.AiModel,
+		Difficulty:   question.Difficulty,
+		UserID:       question.UserID,
+
+		VerificationStatus: question.VerificationStatus,
+		SourcePath:         question.SourcePath,
+		SourceStartLine:    question.SourceStartLine,
+		SourceEndLine:      question.SourceEndLine,
+		CreatedAt:          question.CreatedAt,
+		UpdatedAt:          question.UpdatedAt,
+	}
+	switch question.QuestionType {
+	case models.QuestionTypeFillBlank:
+		_ = json.Unmarshal([]byte(question.Answer), &dto.AcceptedAnswers)
+	case models.QuestionTypeShortAnswer:
+		var answer ShortAnswer
+		if json.Unmarshal([]byte(question.Answer), &answer) == nil {
+			dto.ShortAnswer = &answer
+		}
+	}
+	return dto
 }
 
 // GetPaperDetail 查询试卷详情（包含关联题目）
@@ -262,20 +310,7 @@ func GetPaperDetail(ctx context.Context, paperID, creatorID int64) (*PaperDetail
 			QuestionID:    pq.QuestionID,
 			QuestionOrder: pq.QuestionOrder,
 			Score:         pq.Score,
-			QuestionInfo: QuestionDTO{
-				ID:           question.ID,
-				Title:        question.Title,
-				QuestionType: question.QuestionType,
-				Options:      question.Options,
-				Answer:       question.Answer,
-				Explanation:  question.Explanation,
-				Keywords:     question.Keywords,
-				Language:     question.Language,
-				AiModel:      question.AiModel,
-				UserID:       question.UserID,
-				CreatedAt:    question.CreatedAt,
-				UpdatedAt:    question.UpdatedAt,
-			},
+			QuestionInfo:  newQuestionDTO(question),
 		})
 	}
 
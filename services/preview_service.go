@@ -0,0 +1,590 @@
+package services
+
+import (
+	"CodeQuizAI/config"
+	"CodeQuizAI/dao"
+	"CodeQuizAI/models"
+	"CodeQuizAI/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PreviewSummary 未确认预览的概要
+type PreviewSummary struct {
+	PreviewID    string    `json:"preview_id"`           // 预览批次ID
+	Count        int       `json:"count"`                // 未确认的题目数量
+	Language     string    `json:"language"`             // 编程语言
+	QuestionType string    `json:"question_type"`        // 题型
+	Keywords     string    `json:"keywords,omitempty"`   // 关键词
+	Difficulty   string    `json:"difficulty,omitempty"` // 难度
+	AIModels     []string  `json:"ai_models"`            // 生成题目的模型（发生降级时可能有多个）
+	CreatedAt    time.Time `json:"created_at"`           // 生成时间（最早一道题目的创建时间）
+	AgeSeconds   int64     `json:"age_seconds"`          // 距生成时间的秒数
+}
+
+// PreviewDetail 预览详情
+type PreviewDetail struct {
+	PreviewSummary
+	Questions []*models.TempQuestion `json:"questions"` // 未确认的临时题目（按生成顺序）
+}
+
+// ListPreviewsRequest 预览列表查询参数
+type ListPreviewsRequest struct {
+	Page     int `form:"page"`      // 页码
+	PageSize int `form:"page_size"` // 每页条数
+}
+
+// PreviewListResponse 预览列表响应
+type PreviewListResponse struct {
+	List       []PreviewSummary `json:"list"`       // 预览列表
+	Pagination Pagination       `json:"pagination"` // 分页信息
+}
+
+// previewGroup 按 preview_id 汇总的未确认题目
+type previewGroup struct {
+	PreviewID string
+	FirstID   int64 // 最早一道题目的ID
+	Count     int   // 未确认的题目数量
+}
+
+// ListPreviews 分页查询用户未确认且未过期的预览（按生成时间从新到旧，在数据库中按 preview_id 汇总）
+func ListPreviews(ctx context.Context, userID int64, req ListPreviewsRequest, previewTTL time.Duration) (PreviewListResponse, error) {
+	// 1. 构建查询条件：已过期但清理任务尚未执行的预览不再列出（最早一道题目过期即视为整个预览过期）
+	t := dao.TempQuestion
+	expired := dao.Q.TempQuestion.WithContext(ctx).
+		Select(t.PreviewID).
+		Where(t.UserID.Eq(userID), t.CreatedAt.Lte(time.Now().Add(-previewTTL)))
+	query := dao.Q.TempQuestion.WithContext(ctx).
+		Where(t.UserID.Eq(userID), t.Columns(t.PreviewID).NotIn(expired))
+
+	// 2. 统计预览总数
+	total, err := query.Distinct(t.PreviewID).Count()
+	if err != nil {
+		return PreviewListResponse{}, fmt.Errorf("统计预览总数失败：%w", err)
+	}
+
+	// 3. 按 preview_id 汇总当前页的预览（最早一道题目的ID越大，生成时间越新）
+	var groups []previewGroup
+	if err := query.
+		Select(t.PreviewID, t.ID.Min().As("first_id"), t.ID.Count().As("count")).
+		Group(t.PreviewID).
+		Order(t.ID.Min().Desc()).
+		Limit(req.PageSize).
+		Offset((req.Page - 1) * req.PageSize).
+		Scan(&groups); err != nil {
+		return PreviewListResponse{}, fmt.Errorf("查询预览失败：%w", err)
+	}
+
+	// 4. 补充各预览的生成参数（取最早一道题目）和生成题目的模型
+	summaries, err := loadPreviewSummaries(ctx, userID, groups)
+	if err != nil {
+		return PreviewListResponse{}, err
+	}
+
+	return PreviewListResponse{
+		List: summaries,
+		Pagination: Pagination{
+			Total:      total,
+			Page:       int64(req.Page),
+			PageSize:   int64(req.PageSize),
+			TotalPages: (total + int64(req.PageSize) - 1) / int64(req.PageSize),
+		},
+	}, nil
+}
+
+// loadPreviewSummaries 按汇总结果查询各预览最早一道题目和生成题目的模型，生成预览概要（顺序与 groups 相同）
+func loadPreviewSummaries(ctx context.Context, userID int64, groups []previewGroup) ([]PreviewSummary, error) {
+	summaries := []PreviewSummary{}
+	if len(groups) == 0 {
+		return summaries, nil
+	}
+	t := dao.TempQuestion
+	firstIDs := make([]int64, len(groups))
+	previewIDs := make([]string, len(groups))
+	for i, g := range groups {
+		firstIDs[i], previewIDs[i] = g.FirstID, g.PreviewID
+	}
+
+	// 1. 各预览最早一道题目
+	firsts, err := dao.Q.TempQuestion.WithContext(ctx).
+		Select(t.ID, t.PreviewID, t.Language, t.QuestionType, t.Keywords, t.Difficulty, t.CreatedAt).
+		Where(t.ID.In(firstIDs...)).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询预览失败：%w", err)
+	}
+	firstByID := make(map[int64]*models.TempQuestion, len(firsts))
+	for _, q := range firsts {
+		firstByID[q.ID] = q
+	}
+
+	// 2. 各预览生成题目的模型（按首次出现的顺序）
+	var pairs []struct {
+		PreviewID string
+		AiModel   string
+	}
+	if err := dao.Q.TempQuestion.WithContext(ctx).
+		Select(t.PreviewID, t.AiModel).
+		Where(t.UserID.Eq(userID), t.PreviewID.In(previewIDs...)).
+		Group(t.PreviewID, t.AiModel).
+		Order(t.ID.Min()).
+		Scan(&pairs); err != nil {
+		return nil, fmt.Errorf("查询预览的生成模型失败：%w", err)
+	}
+	modelsByPreview := make(map[string][]string, len(groups))
+	for _, p := range pairs {
+		modelsByPreview[p.PreviewID] = append(modelsByPreview[p.PreviewID], p.AiModel)
+	}
+
+	// 3. 生成预览概要
+	now := time.Now()
+	for _, g := range groups {
+		first, ok := firstByID[g.FirstID]
+		if !ok {
+			continue // 汇总后被确认或丢弃
+		}
+		summary := newPreviewSummary(first, now)
+		summary.Count = g.Count
+		if aiModels, ok := modelsByPreview[g.PreviewID]; ok {
+			summary.AIModels = aiModels
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// GetPreview 查询预览中未确认的临时题目，预览已过期时返回 utils.ErrPreviewExpired
+func GetPreview(ctx context.Context, previewID string, userID int64, previewTTL time.Duration) (*PreviewDetail, error) {
+	questions, err := dao.Q.TempQuestion.WithContext(ctx).
+		Where(dao.TempQuestion.PreviewID.Eq(previewID), dao.TempQuestion.UserID.Eq(userID)).
+		Order(dao.TempQuestion.ID).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询预览失败：%w", err)
+	}
+	if len(questions) == 0 {
+		return nil, utils.ErrPreviewNotFound
+	}
+	if previewExpired(questions[0], previewTTL) {
+		return nil, utils.ErrPreviewExpired
+	}
+	return &PreviewDetail{PreviewSummary: summarizePreviews(questions)[0], Questions: questions}, nil
+}
+
+// summarizePreviews 按 preview_id 汇总临时题目（questions 需按ID排序）
+func summarizePreviews(questions []*models.TempQuestion) []PreviewSummary {
+	summaries := []PreviewSummary{}
+	index := make(map[string]int)
+	now := time.Now()
+	for _, q := range questions {
+		i, ok := index[q.PreviewID]
+		if !ok {
+			i = len(summaries)
+			index[q.PreviewID] = i
+			summaries = append(summaries, newPreviewSummary(q, now))
+		}
+		summary := &summaries[i]
+		summary.Count++
+		if !slices.Contains(summary.AIModels, q.AiModel) {
+			summary.AIModels = append(summary.AIModels, q.AiModel)
+		}
+	}
+	return summaries
+}
+
+// newPreviewSummary 以预览中最早一道题目的生成参数创建预览概要（题目数量和模型由调用方填写）
+func newPreviewSummary(first *models.TempQuestion, now time.Time) PreviewSummary {
+	return PreviewSummary{
+		PreviewID:    first.PreviewID,
+		Language:     first.Language,
+		QuestionType: first.QuestionType,
+		Keywords:     first.Keywords,
+		Difficulty:   first.Difficulty,
+		AIModels:     []string{},
+		CreatedAt:    first.CreatedAt,
+		AgeSeconds:   int64(now.Sub(first.CreatedAt).Seconds()),
+	}
+}
+
+// UpdateTempQuestion 编辑预览中的单道临时题目（原版本保存为历史版本，可撤销）
+func UpdateTempQuestion(ctx context.Context, previewID, tempID string, userID int64, edit TempQuestionEdit, previewTTL time.Duration) (*models.TempQuestion, error) {
+	// 1. 查询临时题目（验证权限、存在性和有效期）
+	temp, err := getTempQuestion(ctx, previewID, tempID, userID, previewTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 应用编辑内容并校验（规则与确认入库时一致）
+	edited, err := applyTempQuestionEdit(*temp, edit)
+	if err != nil {
+		return nil, fmt.Errorf("%w：%v", utils.ErrInvalidEdit, err)
+	}
+
+	// 3. 题目文本有变化时重新与题库比对（比对失败不影响编辑）
+	if edit.Title != "" || edit.Options != "" || edit.CodeSnippet != "" {
+		if index, err := loadSimilarityIndex(ctx, userID, temp.Language); err != nil {
+			log.Printf("近似重复检测失败: %v", err)
+		} else {
+			edited.SimilarQuestionID, edited.Similarity = nil, 0
+			index.markSimilar(&edited)
+		}
+	}
+
+	// 4. 保存原版本并更新
+	if err := saveTempQuestionRevision(ctx, temp, &edited, ""); err != nil {
+		return nil, err
+	}
+	return temp, nil
+}
+
+// DeletePreview 丢弃预览中所有未确认的临时题目（软删除）及其历史版本，返回丢弃的题目数量
+func DeletePreview(ctx context.Context, previewID string, userID int64) (int, error) {
+	// 1. 查询预览中未确认的题目
+	t := dao.TempQuestion
+	var ids []int64
+	if err := dao.Q.TempQuestion.WithContext(ctx).
+		Where(t.PreviewID.Eq(previewID), t.UserID.Eq(userID)).
+		Pluck(t.ID, &ids); err != nil {
+		return 0, fmt.Errorf("查询预览失败：%w", err)
+	}
+	if len(ids) == 0 {
+		return 0, utils.ErrPreviewNotFound
+	}
+
+	// 2. 软删除题目并删除历史版本
+	if _, err := dao.Q.TempQuestion.WithContext(ctx).Where(t.ID.In(ids...)).Delete(); err != nil {
+		return 0, fmt.Errorf("丢弃预览失败：%w", err)
+	}
+	if err := deleteTempQuestionRevisions(ctx, ids); err != nil {
+		log.Printf("警告：临时题目历史版本删除失败，preview_id=%s, err=%v", previewID, err)
+	}
+	return len(ids), nil
+}
+
+// refineTarget 重新生成单道题目时被替换的题目和修改要求
+type refineTarget struct {
+	title       string // 被替换题目的标题
+	instruction string // 修改要求（可为空）
+	revision    int    // 新版本的修订号
+}
+
+// RegenerateQuestionRequest 重新生成单道临时题目的请求参数
+type RegenerateQuestionRequest struct {
+	Instruction string `json:"instruction" binding:"max=500"` // 修改要求（可选，如"难度更高"、"侧重 channel"）
+	AIModel     string `json:"ai_model"`                      // AI模型（可选，默认沿用原题目的模型）
+	Verify      bool   `json:"verify"`                        // 由校验模型校验新题目的答案（可选）
+	VerifyModel string `json:"verify_model"`                  // 校验模型（可选，默认使用 AI_VERIFY_MODEL）
+}
+
+// RegenerateQuestionResult 重新生成单道临时题目的结果
+type RegenerateQuestionResult struct {
+	Question     *models.TempQuestion `json:"question"`               // 替换后的临时题目（temp_id 不变，revision 加1）
+	Rejected     []RejectedQuestion   `json:"rejected,omitempty"`     // 校验未通过（修正后仍不合格）的题目
+	AIModel      string               `json:"ai_model"`               // 实际生成题目的模型
+	FallbackUsed bool                 `json:"fallback_used"`          // 是否发生了模型降级
+	Attempts     []ModelAttempt       `json:"attempts"`               // 各模型的尝试记录
+	Verification *VerificationSummary `json:"verification,omitempty"` // 答案校验结果（开启校验时）
+}
+
+// tempQuestionContent 临时题目中随重新生成或编辑替换的内容（历史版本按此格式保存）
+type tempQuestionContent struct {
+	Title             string  `json:"title"`
+	Options           string  `json:"options"`
+	Answer            string  `json:"answer"`
+	CodeSnippet       string  `json:"code_snippet"`
+	CodeLanguage      string  `json:"code_language"`
+	Explanation       string  `json:"explanation"`
+	Difficulty        string  `json:"difficulty"`
+	AiModel           string  `json:"ai_model"`
+	SimilarQuestionID *int64  `json:"similar_question_id"`
+	Similarity        float64 `json:"similarity"`
+	TemplateVersion   string  `json:"template_version"`
+
+	VerificationStatus string `json:"verification_status"`
+	ReviewerModel      string `json:"reviewer_model"`
+	ReviewerAnswer     string `json:"reviewer_answer"`
+	ReviewerReasoning  string `json:"reviewer_reasoning"`
+
+	SourceStartLine int `json:"source_start_line"`
+	SourceEndLine   int `json:"source_end_line"`
+}
+
+// contentOf 取出临时题目的内容
+func contentOf(temp *models.TempQuestion) tempQuestionContent {
+	return tempQuestionContent{
+		Title:             temp.Title,
+		Options:           temp.Options,
+		Answer:            temp.Answer,
+		CodeSnippet:       temp.CodeSnippet,
+		CodeLanguage:      temp.CodeLanguage,
+		Explanation:       temp.Explanation,
+		Difficulty:        temp.Difficulty,
+		AiModel:           temp.AiModel,
+		SimilarQuestionID: temp.SimilarQuestionID,
+		Similarity:        temp.Similarity,
+		TemplateVersion:   temp.TemplateVersion,
+
+		VerificationStatus: temp.VerificationStatus,
+		ReviewerModel:      temp.ReviewerModel,
+		ReviewerAnswer:     temp.ReviewerAnswer,
+		ReviewerReasoning:  temp.ReviewerReasoning,
+
+		SourceStartLine: temp.SourceStartLine,
+		SourceEndLine:   temp.SourceEndLine,
+	}
+}
+
+// applyTo 用该内容替换临时题目的内容
+func (c tempQuestionContent) applyTo(temp *models.TempQuestion) {
+	temp.Title = c.Title
+	temp.Options = c.Options
+	temp.Answer = c.Answer
+	temp.CodeSnippet = c.CodeSnippet
+	temp.CodeLanguage = c.CodeLanguage
+	temp.Explanation = c.Explanation
+	temp.Difficulty = c.Difficulty
+	temp.AiModel = c.AiModel
+	temp.SimilarQuestionID = c.SimilarQuestionID
+	temp.Similarity = c.Similarity
+	temp.TemplateVersion = c.TemplateVersion
+	temp.VerificationStatus = c.VerificationStatus
+	temp.ReviewerModel = c.ReviewerModel
+	temp.ReviewerAnswer = c.ReviewerAnswer
+	temp.ReviewerReasoning = c.ReviewerReasoning
+	temp.SourceStartLine = c.SourceStartLine
+	temp.SourceEndLine = c.SourceEndLine
+}
+
+// tempGenerateParams 还原临时题目的生成参数。016 迁移之前生成的题目没有保存生成参数，
+// 关键词按逗号拆分 keywords 列，降级模型使用服务端默认配置
+func tempGenerateParams(temp *models.TempQuestion) generateParams {
+	var params generateParams
+	if temp.GenerateParams != "" && json.Unmarshal([]byte(temp.GenerateParams), &params) == nil {
+		return params
+	}
+	if temp.Keywords != "" {
+		params.Keywords = strings.Split(temp.Keywords, ",")
+	}
+	return params
+}
+
+// RegenerateTempQuestion 重新生成预览中的单道临时题目：按原题目的生成参数（按资料出题时含原出处所在的资料段）和修改要求生成一道新题目，
+// 原地替换题目内容（temp_id 不变），原版本保存为历史版本，确认入库前可撤销
+func RegenerateTempQuestion(
+	ctx context.Context,
+	previewID string,
+	tempID string,
+	userID int64,
+	req RegenerateQuestionRequest,
+	cfg *config.Config,
+) (*RegenerateQuestionResult, error) {
+	// 1. 查询临时题目（验证权限、存在性和有效期，过期的预览不再调用模型）
+	temp, err := getTempQuestion(ctx, previewID, tempID, userID, time.Duration(cfg.PreviewTTLHours)*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 按原题目的生成参数（关键词、降级模型等）重新生成一道题目（不使用响应缓存）
+	params := tempGenerateParams(temp)
+	genReq := GenerateQuestionRequest{
+		AIModel:      temp.AiModel,
+		Language:     temp.Language,
+		QuestionType: temp.QuestionType,
+		Keywords:     params.Keywords,
+		Count:        1,
+		Difficulty:   temp.Difficulty,
+		Fallback:     params.Fallback,
+		NoCache:      true,
+		Verify:       req.Verify,
+		VerifyModel:  req.VerifyModel,
+		refine:       &refineTarget{title: temp.Title, instruction: req.Instruction, revision: temp.Revision + 1},
+	}
+	if req.AIModel != "" {
+		genReq.AIModel = req.AIModel
+	}
+	if temp.SourceID != nil {
+		// 按资料出题的题目仍依据原出处所在的资料段重新生成
+		chunks, err := loadSourceChunks(ctx, *temp.SourceID, userID, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if genReq.source = sourceChunkAt(chunks, temp.SourcePath, temp.SourceStartLine); genReq.source == nil {
+			return nil, fmt.Errorf("%w（%s 第%d行已不在资料中）", utils.ErrSourceNotFound, temp.SourcePath, temp.SourceStartLine)
+		}
+	}
+	generated, err := generateChunk(ctx, previewID, userID, genReq, cfg)
+	if err != nil {
+		return nil, err
+	}
+	fresh := generated.Questions[0]
+
+	// 3. 新题目不能与预览中的题目（含被替换的原题目）重复
+	previewQuestions, err := dao.Q.TempQuestion.WithContext(ctx).
+		Where(dao.TempQuestion.PreviewID.Eq(previewID), dao.TempQuestion.UserID.Eq(userID)).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询预览题目失败：%w", err)
+	}
+	for _, q := range previewQuestions {
+		if titleKey(q.Title) == titleKey(fresh.Title) {
+			return nil, utils.ErrDuplicateRegenerated
+		}
+	}
+
+	// 4. 与题库比对，标记最相似的已有题目（比对失败不影响生成）
+	if index, err := loadSimilarityIndex(ctx, userID, temp.Language); err != nil {
+		log.Printf("近似重复检测失败: %v", err)
+	} else {
+		index.markSimilar(&fresh)
+	}
+
+	// 5. 按需由校验模型独立作答
+	var verification *VerificationSummary
+	if genReq.Verify {
+		questions := []models.TempQuestion{fresh}
+		verification = verifyQuestions(ctx, previewID, userID, genReq, cfg, questions)
+		fresh = questions[0]
+	}
+
+	// 6. 保存原版本并原地替换
+	if err := saveTempQuestionRevision(ctx, temp, &fresh, req.Instruction); err != nil {
+		return nil, err
+	}
+
+	return &RegenerateQuestionResult{
+		Question:     temp,
+		Rejected:     generated.Rejected,
+		AIModel:      generated.AIModel,
+		FallbackUsed: generated.FallbackUsed,
+		Attempts:     generated.Attempts,
+		Verification: verification,
+	}, nil
+}
+
+// UndoTempQuestion 撤销最近一次重新生成或编辑，将临时题目恢复到上一个版本
+func UndoTempQuestion(ctx context.Context, previewID, tempID string, userID int64, previewTTL time.Duration) (*models.TempQuestion, error) {
+	// 1. 查询临时题目（验证权限、存在性和有效期）
+	temp, err := getTempQuestion(ctx, previewID, tempID, userID, previewTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 查询最近的历史版本
+	revision, err := dao.Q.TempQuestionRevision.WithContext(ctx).
+		Where(dao.TempQuestionRevision.TempQuestionID.Eq(temp.ID)).
+		Order(dao.TempQuestionRevision.Revision.Desc()).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrNoPreviousRevision
+		}
+		return nil, fmt.Errorf("查询历史版本失败：%w", err)
+	}
+	var content tempQuestionContent
+	if err := json.Unmarshal([]byte(revision.Content), &content); err != nil {
+		return nil, fmt.Errorf("历史版本内容损坏：%w", err)
+	}
+
+	// 3. 恢复历史版本并删除该版本记录
+	currentRevision := temp.Revision
+	content.applyTo(temp)
+	temp.Revision = revision.Revision
+	if err := replaceTempQuestion(ctx, temp, currentRevision, func(tx *dao.Query) error {
+		_, err := tx.TempQuestionRevision.WithContext(ctx).Where(tx.TempQuestionRevision.ID.Eq(revision.ID)).Delete()
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return temp, nil
+}
+
+// saveTempQuestionRevision 将临时题目的当前内容保存为历史版本，再替换为 next 的内容并将修订号加1
+// （temp 随之更新为新版本）
+func saveTempQuestionRevision(ctx context.Context, temp, next *models.TempQuestion, instruction string) error {
+	previous, err := json.Marshal(contentOf(temp))
+	if err != nil {
+		return fmt.Errorf("保存历史版本失败：%w", err)
+	}
+	revision := &models.TempQuestionRevision{
+		TempQuestionID: temp.ID,
+		Revision:       temp.Revision,
+		Content:        string(previous),
+		Instruction:    instruction,
+	}
+	currentRevision := temp.Revision
+	contentOf(next).applyTo(temp)
+	temp.Revision = currentRevision + 1
+	return replaceTempQuestion(ctx, temp, currentRevision, func(tx *dao.Query) error {
+		return tx.TempQuestionRevision.WithContext(ctx).Create(revision)
+	})
+}
+
+// replaceTempQuestion 在同一事务中更新临时题目的内容和修订号并保存或删除历史版本，
+// 题目的修订号已不是 currentRevision（已被其他请求修改）时返回 utils.ErrRevisionConflict
+func replaceTempQuestion(ctx context.Context, temp *models.TempQuestion, currentRevision int, withRevision func(tx *dao.Query) error) error {
+	err := dao.Q.Transaction(func(tx *dao.Query) error {
+		t := tx.TempQuestion
+		info, err := t.WithContext(ctx).
+			Where(t.ID.Eq(temp.ID), t.Revision.Eq(currentRevision)).
+			Select(t.Title, t.Options, t.Answer, t.CodeSnippet, t.CodeLanguage, t.Explanation, t.Difficulty,
+				t.AiModel, t.SimilarQuestionID, t.Similarity, t.TemplateVersion, t.Revision,
+				t.VerificationStatus, t.ReviewerModel, t.ReviewerAnswer, t.ReviewerReasoning,
+				t.SourceStartLine, t.SourceEndLine).
+			Updates(temp)
+		if err != nil {
+			return err
+		}
+		if info.RowsAffected == 0 {
+			return utils.ErrRevisionConflict
+		}
+		return withRevision(tx)
+	})
+	if err != nil {
+		if errors.Is(err, utils.ErrRevisionConflict) {
+			return err
+		}
+		return fmt.Errorf("更新临时题目失败：%w", err)
+	}
+	return nil
+}
+
+// getTempQuestion 查询用户预览中的单道临时题目（已确认入库的题目已被软删除），预览已过期时返回 utils.ErrPreviewExpired
+func getTempQuestion(ctx context.Context, previewID, tempID string, userID int64, previewTTL time.Duration) (*models.TempQuestion, error) {
+	temp, err := dao.Q.TempQuestion.WithContext(ctx).
+		Where(
+			dao.TempQuestion.PreviewID.Eq(previewID),
+			dao.TempQuestion.TempID.Eq(tempID),
+			dao.TempQuestion.UserID.Eq(userID),
+		).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrTempQuestionNotFound
+		}
+		return nil, fmt.Errorf("查询临时题目失败：%w", err)
+	}
+	if previewExpired(temp, previewTTL) {
+		return nil, utils.ErrPreviewExpired
+	}
+	return temp, nil
+}
+
+// previewExpired 临时题目是否已过期：已被清理任务标记过期，或已超过有效期但清理任务尚未执行
+func previewExpired(temp *models.TempQuestion, previewTTL time.Duration) bool {
+	return temp.ExpiredAt != nil || time.Since(temp.CreatedAt) > previewTTL
+}
+
+// deleteTempQuestionRevisions 删除临时题目的历史版本（确认入库后不再需要撤销）
+func deleteTempQuestionRevisions(ctx context.Context, tempQuestionIDs []int64) error {
+	_, err := dao.Q.TempQuestionRevision.WithContext(ctx).
+		Where(dao.TempQuestionRevision.TempQuestionID.In(tempQuestionIDs...)).
+		Delete()
+	return err
+}
@@ -0,0 +1,428 @@
+package services
+
+import (
+	"CodeQuizAI/dao"
+	"CodeQuizAI/models"
+	"CodeQuizAI/utils"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// PromptData 提示语模板可使用的变量
+type PromptData struct {
+	Count            int      // 生成数量
+	Language         string   // 编程语言
+	QuestionType     string   // 题型（single/multiple/true_false/fill_blank/short_answer）
+	QuestionTypeName string   // 题型中文名称
+	Keywords         []string // 关键词
+	Difficulty       string   // 难度（简单/中等/困难，未指定时为空）
+	Model            string   // AI模型
+	Schema           string   // 输出格式示例（因题型而异）
+}
+
+// promptFuncs 模板中可使用的函数
+var promptFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// outputSchema 题目输出格式示例（未知题型使用单选题的格式）
+func outputSchema(questionType string) string {
+	if spec, ok := questionTypes[questionType]; ok {
+		return spec.Schema
+	}
+	return questionTypes[models.QuestionTypeSingle].Schema
+}
+
+// newPromptData 根据生成请求构造模板变量
+func newPromptData(req GenerateQuestionRequest, model string) PromptData {
+	return PromptData{
+		Count:            req.Count,
+		Language:         req.Language,
+		QuestionType:     req.QuestionType,
+		QuestionTypeName: questionTypeName(req.QuestionType),
+		Keywords:         req.Keywords,
+		Difficulty:       difficultyName(req.Difficulty),
+		Model:            model,
+		Schema:           outputSchema(req.QuestionType),
+	}
+}
+
+// executePromptTemplate 解析并渲染模板内容
+func executePromptTemplate(name, content string, data PromptData) (string, error) {
+	tmpl, err := template.New(name).Funcs(promptFuncs).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("模板解析失败：%w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("模板渲染失败：%w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// templateVersion 模板版本标识（如 default@1）
+func templateVersion(t *models.PromptTemplate) string {
+	return fmt.Sprintf("%s@%d", t.Name, t.Version)
+}
+
+// templateScore 计算模板与生成参数的匹配度：限定条件不符时不可用，
+// 否则限定的条件越多越优先（语言 > 题型 > 模型）
+func templateScore(t *models.PromptTemplate, language, questionType, model string) (int, bool) {
+	score := 0
+	for _, c := range []struct {
+		want, got string
+		weight    int
+	}{
+		{t.Language, language, 4},
+		{t.QuestionType, questionType, 2},
+		{t.AiModel, model, 1},
+	} {
+		if c.want == "" {
+			continue
+		}
+		if !strings.EqualFold(c.want, c.got) {
+			return 0, false
+		}
+		score += c.weight
+	}
+	return score, true
+}
+
+// selectPromptTemplate 从激活的模板中选择最匹配的一个（匹配度相同时取最新创建的）
+func selectPromptTemplate(ctx context.Context, language, questionType, model string) (*models.PromptTemplate, error) {
+	templates, err := dao.Q.PromptTemplate.WithContext(ctx).
+		Where(dao.PromptTemplate.Active.Is(true)).
+		Order(dao.PromptTemplate.ID.Desc()).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询提示语模板失败：%w", err)
+	}
+
+	var best *models.PromptTemplate
+	bestScore := -1
+	for _, t := range templates {
+		if score, ok := templateScore(t, language, questionType, model); ok && score > bestScore {
+			best, bestScore = t, score
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("%w（语言：%s，题型：%s，模型：%s）", utils.ErrNoPromptTemplate, language, questionType, model)
+	}
+	return best, nil
+}
+
+// renderPrompt 选择模板并渲染生成题目的提示语，同时返回模板版本
+func renderPrompt(ctx context.Context, req GenerateQuestionRequest, model string) (prompt, version string, err error) {
+	t, err := selectPromptTemplate(ctx, req.Language, req.QuestionType, model)
+	if err != nil {
+		return "", "", err
+	}
+	prompt, err = executePromptTemplate(templateVersion(t), t.Content, newPromptData(req, model))
+	if err != nil {
+		return "", "", fmt.Errorf("提示语模板 %s 不可用：%w", templateVersion(t), err)
+	}
+	if req.source != nil {
+		// 按资料出题时给出资料内容（带行号）
+		prompt += "\n\n" + req.source.prompt()
+	}
+	if req.parts > 1 {
+		// 分批生成时提示模型各批次覆盖不同的知识点，减少批次间的重复题目
+		prompt += fmt.Sprintf("\n\n这是分批生成的第%d/%d批，请与其他批次覆盖不同的知识点，避免出题重复。", req.part, req.parts)
+	}
+	if req.refine != nil {
+		// 重新生成单道题目时给出被替换的题目和修改要求
+		prompt += "\n\n请重新生成一道题目，替换下面这道题目，不要与它重复：\n" + req.refine.title
+		if req.refine.instruction != "" {
+			prompt += "\n修改要求：" + req.refine.instruction
+		}
+	}
+	return prompt, templateVersion(t), nil
+}
+
+// SeedPromptTemplates 导入模板目录中的 *.tmpl 文件：
+// 数据库中尚不存在的版本会被新增，同名模板没有激活版本时激活文件中的最高版本。
+// 已存在的版本不会被文件覆盖（修改模板请提升版本号）
+func SeedPromptTemplates(ctx context.Context, dir string) error {
+	// 1. 读取模板文件
+	files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return fmt.Errorf("读取模板目录失败：%w", err)
+	}
+
+	latest := make(map[string]*models.PromptTemplate) // 每个名称在文件中的最高版本
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("读取模板文件失败：%w", err)
+		}
+		t, err := parseTemplateFile(strings.TrimSuffix(filepath.Base(file), ".tmpl"), string(data))
+		if err != nil {
+			return fmt.Errorf("模板文件 %s 格式错误：%w", filepath.Base(file), err)
+		}
+
+		// 2. 新增数据库中不存在的版本
+		existing, err := dao.Q.PromptTemplate.WithContext(ctx).
+			Where(dao.PromptTemplate.Name.Eq(t.Name), dao.PromptTemplate.Version.Eq(t.Version)).
+			First()
+		switch {
+		case err == nil:
+			if existing.Content != t.Content {
+				log.Printf("警告：模板文件 %s 与已导入的 %s 内容不同，已忽略（修改模板请提升版本号）", filepath.Base(file), templateVersion(t))
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := dao.Q.PromptTemplate.WithContext(ctx).Create(t); err != nil {
+				return fmt.Errorf("导入模板 %s 失败：%w", templateVersion(t), err)
+			}
+			log.Printf("已导入提示语模板: %s", templateVersion(t))
+		default:
+			return fmt.Errorf("查询提示语模板失败：%w", err)
+		}
+
+		if prev, ok := latest[t.Name]; !ok || t.Version > prev.Version {
+			latest[t.Name] = t
+		}
+	}
+
+	// 3. 没有激活版本的模板激活文件中的最高版本
+	for name, t := range latest {
+		active, err := dao.Q.PromptTemplate.WithContext(ctx).
+			Where(dao.PromptTemplate.Name.Eq(name), dao.PromptTemplate.Active.Is(true)).
+			Count()
+		if err != nil {
+			return fmt.Errorf("查询提示语模板失败：%w", err)
+		}
+		if active > 0 {
+			continue
+		}
+		if _, err := dao.Q.PromptTemplate.WithContext(ctx).
+			Where(dao.PromptTemplate.Name.Eq(name), dao.PromptTemplate.Version.Eq(t.Version)).
+			Update(dao.PromptTemplate.Active, true); err != nil {
+			return fmt.Errorf("激活模板 %s 失败：%w", templateVersion(t), err)
+		}
+	}
+	return nil
+}
+
+// parseTemplateFile 解析模板文件：--- 之前为 "键: 值" 形式的元数据
+// （name、version、language、question_type、ai_model），之后为模板内容
+func parseTemplateFile(defaultName, data string) (*models.PromptTemplate, error) {
+	header, content, ok := strings.Cut(strings.ReplaceAll(data, "\r\n", "\n"), "\n---\n")
+	if !ok {
+		return nil, errors.New("缺少 --- 分隔的元数据")
+	}
+
+	t := &models.PromptTemplate{
+		Name:    defaultName,
+		Content: strings.TrimSpace(content),
+		Source:  models.TemplateSourceFile,
+	}
+	for _, line := range strings.Split(header, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "name":
+			t.Name = value
+		case "version":
+			version, err := strconv.Atoi(value)
+			if err != nil || version <= 0 {
+				return nil, fmt.Errorf("version 必须是正整数，当前值: %s", value)
+			}
+			t.Version = version
+		case "language":
+			t.Language = value
+		case "question_type":
+			t.QuestionType = value
+		case "ai_model":
+			t.AiModel = value
+		}
+	}
+	if t.Version == 0 {
+		return nil, errors.New("缺少 version")
+	}
+
+	// 校验适用题型和模板语法
+	if t.QuestionType != "" && !IsValidQuestionType(t.QuestionType) {
+		return nil, fmt.Errorf("不支持的题型：%s", t.QuestionType)
+	}
+	if _, err := executePromptTemplate(t.Name, t.Content, samplePromptData()); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// samplePromptData 校验模板时使用的示例变量
+func samplePromptData() PromptData {
+	return newPromptData(GenerateQuestionRequest{
+		AIModel:      "mock",
+		Language:     "Go",
+		QuestionType: models.QuestionTypeSingle,
+		Keywords:     []string{"goroutine"},
+		Count:        1,
+	}, "mock")
+}
+
+// ListPromptTemplates 查询提示语模板（可按名称筛选），按名称和版本排序
+func ListPromptTemplates(ctx context.Context, name string) ([]*models.PromptTemplate, error) {
+	query := dao.Q.PromptTemplate.WithContext(ctx)
+	if name != "" {
+		query = query.Where(dao.PromptTemplate.Name.Eq(name))
+	}
+	templates, err := query.Order(dao.PromptTemplate.Name.Asc(), dao.PromptTemplate.Version.Desc()).Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询提示语模板失败：%w", err)
+	}
+	return templates, nil
+}
+
+// CreatePromptTemplateRequest 新增模板版本的请求参数
+type CreatePromptTemplateRequest struct {
+	Name         string `json:"name" binding:"required,max=100"` // 模板名称（已存在时新增一个版本）
+	Language     string `json:"language"`                        // 适用的编程语言（可选）
+	QuestionType string `json:"question_type"`                   // 适用的题型（可选）
+	AIModel      string `json:"ai_model"`                        // 适用的AI模型（可选）
+	Content      string `json:"content" binding:"required"`      // 模板内容
+	Activate     bool   `json:"activate"`                        // 是否立即激活
+}
+
+// CreatePromptTemplate 新增模板版本（版本号自动递增）
+func CreatePromptTemplate(ctx context.Context, req CreatePromptTemplateRequest) (*models.PromptTemplate, error) {
+	// 1. 校验适用题型和模板语法
+	if req.QuestionType != "" && !IsValidQuestionType(req.QuestionType) {
+		return nil, fmt.Errorf("不支持的题型：%s", req.QuestionType)
+	}
+	if _, err := executePromptTemplate(req.Name, req.Content, samplePromptData()); err != nil {
+		return nil, err
+	}
+
+	// 2. 计算新版本号
+	latest, err := dao.Q.PromptTemplate.WithContext(ctx).
+		Where(dao.PromptTemplate.Name.Eq(req.Name)).
+		Order(dao.PromptTemplate.Version.Desc()).
+		First()
+	version := 1
+	switch {
+	case err == nil:
+		version = latest.Version + 1
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("查询提示语模板失败：%w", err)
+	}
+
+	// 3. 保存新版本
+	t := &models.PromptTemplate{
+		Name:         req.Name,
+		Version:      version,
+		Language:     req.Language,
+		QuestionType: req.QuestionType,
+		AiModel:      req.AIModel,
+		Content:      req.Content,
+		Source:       models.TemplateSourceAPI,
+	}
+	if err := dao.Q.PromptTemplate.WithContext(ctx).Create(t); err != nil {
+		return nil, fmt.Errorf("保存提示语模板失败：%w", err)
+	}
+
+	// 4. 按需激活
+	if req.Activate {
+		return ActivatePromptTemplate(ctx, t.ID)
+	}
+	return t, nil
+}
+
+// PreviewPromptTemplateRequest 预览渲染模板的参数（未传的使用示例值）
+type PreviewPromptTemplateRequest struct {
+	Language     string   `json:"language"`
+	QuestionType string   `json:"question_type"`
+	Keywords     []string `json:"keywords"`
+	Count        int      `json:"count"`
+	Difficulty   string   `json:"difficulty"`
+	AIModel      string   `json:"ai_model"`
+}
+
+// PreviewPromptTemplate 使用给定参数渲染指定模板版本
+func PreviewPromptTemplate(ctx context.Context, templateID int64, req PreviewPromptTemplateRequest) (string, error) {
+	// 1. 查询模板
+	t, err := getPromptTemplate(ctx, templateID)
+	if err != nil {
+		return "", err
+	}
+
+	// 2. 合并示例参数（题型默认使用模板适用的题型）
+	genReq := GenerateQuestionRequest{
+		AIModel:      "mock",
+		Language:     "Go",
+		QuestionType: models.QuestionTypeSingle,
+		Keywords:     req.Keywords,
+		Count:        1,
+	}
+	if t.QuestionType != "" {
+		genReq.QuestionType = t.QuestionType
+	}
+	if req.AIModel != "" {
+		genReq.AIModel = req.AIModel
+	}
+	if req.Language != "" {
+		genReq.Language = req.Language
+	}
+	if req.QuestionType != "" {
+		genReq.QuestionType = req.QuestionType
+	}
+	if req.Count > 0 {
+		genReq.Count = req.Count
+	}
+	if req.Difficulty != "" {
+		genReq.Difficulty = req.Difficulty
+	}
+
+	// 3. 渲染
+	return executePromptTemplate(templateVersion(t), t.Content, newPromptData(genReq, genReq.AIModel))
+}
+
+// ActivatePromptTemplate 激活指定模板版本（同名模板的其他版本自动停用）
+func ActivatePromptTemplate(ctx context.Context, templateID int64) (*models.PromptTemplate, error) {
+	t, err := getPromptTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = dao.Q.Transaction(func(tx *dao.Query) error {
+		if _, err := tx.PromptTemplate.WithContext(ctx).
+			Where(tx.PromptTemplate.Name.Eq(t.Name), tx.PromptTemplate.ID.Neq(t.ID)).
+			Update(tx.PromptTemplate.Active, false); err != nil {
+			return err
+		}
+		_, err := tx.PromptTemplate.WithContext(ctx).
+			Where(tx.PromptTemplate.ID.Eq(t.ID)).
+			Update(tx.PromptTemplate.Active, true)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("激活提示语模板失败：%w", err)
+	}
+
+	t.Active = true
+	return t, nil
+}
+
+// getPromptTemplate 按ID查询模板
+func getPromptTemplate(ctx context.Context, templateID int64) (*models.PromptTemplate, error) {
+	t, err := dao.Q.PromptTemplate.WithContext(ctx).Where(dao.PromptTemplate.ID.Eq(templateID)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrTemplateNotFound
+		}
+		return nil, fmt.Errorf("查询提示语模板失败：%w", err)
+	}
+	return t, nil
+}
@@ -1,29 +1,39 @@
 package services
 
 import (
+	"CodeQuizAI/ai"
 	"CodeQuizAI/config"
 	"CodeQuizAI/dao"
 	"CodeQuizAI/models"
 	"CodeQuizAI/utils"
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
 	"log"
-	"net/http"
 	"strings"
 	"time"
 )
 
 // GenerateQuestionRequest 生成题目的请求参数
 type GenerateQuestionRequest struct {
-	AIModel      string   `json:"ai_model" binding:"required,oneof=tongyi deepseek"`      // 支持的AI模型
-	Language     string   `json:"language" binding:"required"`                            // 编程语言
-	QuestionType string   `json:"question_type" binding:"required,oneof=single multiple"` // 题型
-	Keywords     []string `json:"keywords"`                                               // 关键词（可选）
-	Count        int      `json:"count" binding:"min=1,max=10"`                           // 生成数量（1-10）
+	AIModel      string   `json:"ai_model" binding:"required"`                           // AI模型（取值见已启用的模型列表）
+	Language     string   `json:"language" binding:"required"`                           // 编程语言
+	QuestionType string   `json:"question_type" binding:"required"`                      // 题型（取值见 QuestionTypes）
+	Keywords     []string `json:"keywords"`                                              // 关键词（可选）
+	Count        int      `json:"count" binding:"min=1"`                                 // 生成数量（上限见 GENERATE_MAX_COUNT）
+	Difficulty   string   `json:"difficulty" binding:"omitempty,oneof=easy medium hard"` // 难度（可选）
+	Fallback     []string `json:"fallback"`                                              // 降级模型列表（可选，不传使用服务端默认配置，传空数组禁用降级）
+	NoCache      bool     `json:"no_cache"`                                              // 跳过响应缓存，重新调用模型（可选）
+	Verify       bool     `json:"verify"`                                                // 生成后由校验模型独立作答，校验生成的答案（可选）
+	VerifyModel  string   `json:"verify_model"`                                          // 校验模型（可选，默认使用 AI_VERIFY_MODEL）
+	MockFailure  string   `json:"mock_failure" binding:"max=32"`                         // 模拟的故障（可选，仅 mock 模型使用，如 "error" 或 "error@2"，用于测试）
+	SourceID     int64    `json:"source_id"`                                             // 出题资料ID（可选，按上传的资料内容出题）
+
+	part, parts int           // 分批生成时的批次序号（从1开始）和总批数（未分批时为0）
+	refine      *refineTarget // 重新生成单道题目时被替换的题目和修改要求（其他情况为 nil）
+	source      *sourceChunk  // 按资料出题时本批次依据的资料段（其他情况为 nil）
 }
 
 // IsLanguageSupported 检查编程语言是否在支持列表中
@@ -36,242 +46,335 @@ func IsLanguageSupported(lang string, supported []string) bool {
 	return false
 }
 
-// GenerateQuestions 生成题目核心逻辑
+// IsValidDifficulty 检查难度取值是否合法（easy/medium/hard）
+func IsValidDifficulty(difficulty string) bool {
+	switch difficulty {
+	case models.DifficultyEasy, models.DifficultyMedium, models.DifficultyHard:
+		return true
+	}
+	return false
+}
+
+// IsValidVerificationStatus 检查答案校验状态取值是否合法（unverified/verified/flagged）
+func IsValidVerificationStatus(status string) bool {
+	switch status {
+	case models.VerificationUnverified, models.VerificationVerified, models.VerificationFlagged:
+		return true
+	}
+	return false
+}
+
+// difficultyName 难度的中文名称（用于提示语）
+func difficultyName(difficulty string) string {
+	switch difficulty {
+	case models.DifficultyEasy:
+		return "简单"
+	case models.DifficultyMedium:
+		return "中等"
+	case models.DifficultyHard:
+		return "困难"
+	}
+	return ""
+}
+
+// GenerateQuestions 生成题目核心逻辑：数量超过每批上限（或按资料出题、资料有多段）时分批并发生成，
+// 结果合并到同一个 preview_id
 func GenerateQuestions(
 	ctx context.Context,
 	previewID string,
 	userID int64,
 	req GenerateQuestionRequest,
 	cfg *config.Config,
-) ([]models.TempQuestion, error) {
-	// 1. 获取AI模型对应的API Key
-	apiKey, err := getAPIKeyByModel(req.AIModel, cfg)
+) (*GenerateQuestionsResult, error) {
+	// 1. 拆分批次并生成题目（只有一批时一次调用完成）
+	reqs, err := planRequests(ctx, userID, req, cfg)
 	if err != nil {
 		return nil, err
 	}
-
-	// 2. 构造AI提示语
-	prompt := buildPrompt(req)
-
-	// 3. 调用AI接口
-	aiResp, err := callAIAPI(ctx, req.AIModel, apiKey, prompt)
+	var result *GenerateQuestionsResult
+	if len(reqs) > 1 {
+		result, err = generateInChunks(ctx, previewID, userID, reqs, cfg)
+	} else {
+		result, err = generateChunk(ctx, previewID, userID, reqs[0], cfg)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("AI接口调用失败：%w", err)
+		return nil, err
 	}
 
-	// 4. 解析AI返回结果
-	tempQuestions, err := parseAIResponse(aiResp, req, previewID, userID)
-	if err != nil {
-		return nil, fmt.Errorf("解析AI结果失败：%w", err)
+	// 2. 与题库比对，标记最相似的已有题目（比对失败不影响生成）
+	if index, err := loadSimilarityIndex(ctx, userID, req.Language); err != nil {
+		log.Printf("近似重复检测失败: %v", err)
+	} else {
+		for i := range result.Questions {
+			index.markSimilar(&result.Questions[i])
+		}
+	}
+
+	// 3. 按需由校验模型独立作答，答案不一致的题目标记为待复核
+	if req.Verify {
+		result.Verification = verifyQuestions(ctx, previewID, userID, req, cfg, result.Questions)
 	}
 
-	// 5. 存储到临时表
-	if err := saveTempQuestions(ctx, tempQuestions); err != nil {
+	// 4. 存储到临时表
+	if err := saveTempQuestions(ctx, result.Questions); err != nil {
 		return nil, fmt.Errorf("存储临时题目失败：%w", err)
 	}
 
-	return tempQuestions, nil
+	return result, nil
 }
 
-// getAPIKeyByModel 根据模型获取API Key
-func getAPIKeyByModel(model string, cfg *config.Config) (string, error) {
-	switch model {
-	case "tongyi":
-		if cfg.TongyiAPIKey == "" {
-			return "", errors.New("通义千问API Key未配置")
-		}
-		return cfg.TongyiAPIKey, nil
-	case "deepseek":
-		if cfg.DeepseekAPIKey == "" {
-			return "", errors.New("DeepSeek API Key未配置")
+// planRequests 拆分生成批次：按资料出题时按资料分段分配题目数量，否则按每批上限拆分
+func planRequests(ctx context.Context, userID int64, req GenerateQuestionRequest, cfg *config.Config) ([]GenerateQuestionRequest, error) {
+	if req.SourceID > 0 {
+		chunks, err := loadSourceChunks(ctx, req.SourceID, userID, cfg)
+		if err != nil {
+			return nil, err
 		}
-		return cfg.DeepseekAPIKey, nil
-	default:
-		return "", errors.New("不支持的AI模型")
+		return planSourceRequests(req, chunks, cfg.GenerateChunkSize), nil
 	}
-}
 
-// buildPrompt 构造AI提示语
-func buildPrompt(req GenerateQuestionRequest) string {
-	keywords := ""
-	if len(req.Keywords) > 0 {
-		keywords = fmt.Sprintf("，关键词：%s", strings.Join(req.Keywords, "、"))
+	sizes := chunkSizes(req.Count, cfg.GenerateChunkSize)
+	if len(sizes) <= 1 {
+		return []GenerateQuestionRequest{req}, nil
+	}
+	reqs := make([]GenerateQuestionRequest, len(sizes))
+	for i, size := range sizes {
+		reqs[i] = req
+		reqs[i].Count = size
+		reqs[i].part, reqs[i].parts = i+1, len(sizes)
 	}
+	return reqs, nil
+}
 
-	questionType := "单选题"
-	if req.QuestionType == "multiple" {
-		questionType = "多选题"
+// generateChunk 生成一批题目（不落库）：占用并发名额后按模型顺序尝试，直到某个模型生成出有效题目
+func generateChunk(
+	ctx context.Context,
+	previewID string,
+	userID int64,
+	req GenerateQuestionRequest,
+	cfg *config.Config,
+) (*GenerateQuestionsResult, error) {
+	// 1. 等待并发名额
+	release, err := generationLimiter(cfg).acquire(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
+	defer release()
+
+	// 2. 确定模型尝试顺序：请求的模型 + 降级模型列表
+	modelChain := buildModelChain(req, cfg)
 
-	return fmt.Sprintf(`请生成%d道关于%s语言的%s,围绕%s这个知识点。
-每道题必须包含：
-- title：题目标题（字符串）
-- options：选项（数组，如["A. 选项1", "B. 选项2"]）
-- answer：答案（字符串，如"A"或"AB"）
-- explanation：解析（可选）
+	// 3. 按顺序尝试，直到某个模型生成出有效题目
+	result := &GenerateQuestionsResult{}
+	var lastErr error
+	for _, model := range modelChain {
+		batch, err := generateWithModel(ctx, model, previewID, userID, req, cfg)
+		if err != nil {
+			lastErr = err
+			result.Attempts = append(result.Attempts, ModelAttempt{Model: model, Error: err.Error()})
+			// 请求已取消时不再尝试后续模型
+			if ctx.Err() != nil {
+				break
+			}
+			log.Printf("AI模型 %s 生成失败，尝试下一个模型: %v", model, err)
+			continue
+		}
 
-严格返回JSON数组，无额外内容：
-[
-  {"title":"...","options":["..."],"answer":"...","explanation":"..."}
-]`, req.Count, req.Language, questionType, keywords)
+		result.Attempts = append(result.Attempts, ModelAttempt{Model: model, Cached: batch.Cached})
+		result.Questions = batch.Questions
+		result.ParseErrors = batch.ParseErrors
+		result.Rejected = batch.Rejected
+		result.AIModel = model
+		result.FallbackUsed = model != req.AIModel
+		return result, nil
+	}
+	if len(result.Attempts) > 1 {
+		return result, fmt.Errorf("所有模型均生成失败（已尝试 %d 个）：%w", len(result.Attempts), lastErr)
+	}
+	return result, lastErr
 }
 
-// callAIAPI 调用AI接口
-func callAIAPI(ctx context.Context, model, apiKey, prompt string) (string, error) {
-	switch model {
-	case "tongyi":
-		// 通义千问API请求
-		url := "https://dashscope.aliyuncs.com/api/v1/services/aigc/text-generation/generation"
-		reqBody, _ := json.Marshal(map[string]interface{}{
-			"model": "qwen-turbo",
-			"input": map[string]string{"prompt": prompt},
-		})
+// GenerateQuestionsResult 题目生成结果（异步任务以JSON保存，题目已保存在 temp_questions 表中，不重复保存）
+type GenerateQuestionsResult struct {
+	Questions    []models.TempQuestion `json:"-"`                       // 生成的临时题目
+	ParseErrors  []ItemParseError      `json:"parse_errors,omitempty"`  // 解析失败被跳过的题目
+	Rejected     []RejectedQuestion    `json:"rejected,omitempty"`      // 校验未通过（修正后仍不合格）的题目
+	AIModel      string                `json:"ai_model"`                // 实际生成题目的模型（分批生成时为第一个成功批次的模型）
+	FallbackUsed bool                  `json:"fallback_used"`           // 是否发生了模型降级
+	Attempts     []ModelAttempt        `json:"attempts"`                // 各模型的尝试记录（按尝试顺序）
+	FailedChunks []ChunkFailure        `json:"failed_chunks,omitempty"` // 生成失败的批次（分批生成时）
+	Deduplicated int                   `json:"deduplicated,omitempty"`  // 与其他批次重复而被去掉的题目数量（分批生成时）
+	Verification *VerificationSummary  `json:"verification,omitempty"`  // 答案校验结果（开启校验时）
+}
 
-		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBody))
-		if err != nil {
-			return "", err
-		}
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+apiKey)
+// generatedBatch 单个模型的生成结果
+type generatedBatch struct {
+	Questions   []models.TempQuestion // 校验通过的临时题目
+	ParseErrors []ItemParseError      // 解析失败被跳过的题目
+	Rejected    []RejectedQuestion    // 校验未通过的题目
+	Cached      bool                  // 是否使用了缓存的模型输出
+}
 
-		client := &http.Client{}
-		resp, err := client.Do(req)
-		if err != nil {
-			return "", err
-		}
-		defer resp.Body.Close()
-
-		// 解析通义千问响应
-		var tongyiResp struct {
-			Output struct {
-				Text string `json:"text"`
-			} `json:"output"`
-			Code    string `json:"code"`
-			Message string `json:"message"`
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&tongyiResp); err != nil {
-			return "", err
-		}
-		if tongyiResp.Code != "" {
-			return "", errors.New(tongyiResp.Message)
-		}
-		return tongyiResp.Output.Text, nil
-
-	case "deepseek":
-		// DeepSeek API调用逻辑（参考官方文档）
-		// 文档地址：https://platform.deepseek.com/api-docs/
-		url := "https://api.deepseek.com/v1/chat/completions"
-
-		// 构造请求体（DeepSeek使用chat completions格式）
-		reqBody, err := json.Marshal(map[string]interface{}{
-			"model": "deepseek-chat",
-			"messages": []map[string]string{
-				{
-					"role":    "user",
-					"content": prompt, // 复用之前构造的提示语
-				},
-			},
-			"temperature": 0.7, // 控制随机性，0-1之间
-			"response_format": map[string]string{
-				"type": "json_object", // 强制返回JSON格式
-			},
-		})
-		if err != nil {
-			return "", fmt.Errorf("构造请求体失败: %w", err)
-		}
+// ModelAttempt 单个模型的尝试记录
+type ModelAttempt struct {
+	Model  string `json:"model"`            // 模型名
+	Error  string `json:"error,omitempty"`  // 失败原因（成功时为空）
+	Chunk  int    `json:"chunk,omitempty"`  // 批次序号（分批生成时）
+	Cached bool   `json:"cached,omitempty"` // 是否使用了缓存的模型输出
+}
 
-		// 创建带上下文的请求
-		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBody))
-		if err != nil {
-			return "", err
-		}
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+apiKey) // DeepSeek使用Bearer认证
+// buildModelChain 构造模型尝试顺序（去重），请求未指定降级列表时使用服务端默认配置
+func buildModelChain(req GenerateQuestionRequest, cfg *config.Config) []string {
+	fallback := req.Fallback
+	if fallback == nil {
+		fallback = cfg.AIFallbackModels
+	}
 
-		// 发送请求
-		client := &http.Client{}
-		resp, err := client.Do(req)
-		if err != nil {
-			return "", fmt.Errorf("请求发送失败: %w", err)
+	chain := []string{req.AIModel}
+	seen := map[string]bool{req.AIModel: true}
+	for _, model := range fallback {
+		if model == "" || seen[model] {
+			continue
 		}
-		defer resp.Body.Close()
+		seen[model] = true
+		chain = append(chain, model)
+	}
+	return chain
+}
 
-		// 处理响应
-		var deepseekResp struct {
-			ID      string `json:"id"`
-			Object  string `json:"object"`
-			Created int64  `json:"created"`
-			Model   string `json:"model"`
-			Choices []struct {
-				Message struct {
-					Role    string `json:"role"`
-					Content string `json:"content"` // 包含生成的题目JSON
-				} `json:"message"`
-				FinishReason string `json:"finish_reason"`
-				Index        int    `json:"index"`
-			} `json:"choices"`
-			Error *struct {
-				Message string `json:"message"`
-				Code    string `json:"code"`
-			} `json:"error"`
-		}
+// generateWithModel 使用指定模型生成、解析并校验题目（不落库）
+func generateWithModel(
+	ctx context.Context,
+	model string,
+	previewID string,
+	userID int64,
+	req GenerateQuestionRequest,
+	cfg *config.Config,
+) (*generatedBatch, error) {
+	// 1. 获取AI模型（从注册表中查找已启用的模型）
+	provider, err := ai.Get(model)
+	if err != nil {
+		return nil, err
+	}
 
-		if err := json.NewDecoder(resp.Body).Decode(&deepseekResp); err != nil {
-			return "", fmt.Errorf("响应解析失败: %w", err)
-		}
+	// 2. 选择模板并构造AI提示语
+	prompt, version, err := renderPrompt(ctx, req, model)
+	if err != nil {
+		return nil, err
+	}
 
-		// 处理错误响应
-		if deepseekResp.Error != nil {
-			return "", fmt.Errorf("DeepSeek API错误: %s (代码: %s)", deepseekResp.Error.Message, deepseekResp.Error.Code)
-		}
+	// 3. 调用AI接口（优先使用缓存的输出，记录用量）
+	purpose := models.AICallPurposeGenerate
+	if req.refine != nil {
+		purpose = models.AICallPurposeRegenerate
+	}
+	call := newAICall(cfg, userID, previewID, purpose)
+	cache := newResponseCache(cfg, req, model, version, prompt)
+	aiResp, err := call.chatCached(withMockFailure(ctx, req), provider, cache, &ai.Request{Prompt: prompt})
+	if err != nil {
+		return nil, fmt.Errorf("AI接口调用失败：%w", err)
+	}
 
-		// 提取生成的内容（确保有返回结果）
-		if len(deepseekResp.Choices) == 0 || deepseekResp.Choices[0].Message.Content == "" {
-			return "", errors.New("DeepSeek未返回有效内容")
-		}
+	// 4. 解析AI返回结果（单道题目解析失败不影响其他题目）
+	aiQuestions, parseErrs, err := extractAIQuestions(aiResp.Content)
+	if err != nil {
+		return nil, fmt.Errorf("解析AI结果失败：%w", err)
+	}
 
-		return deepseekResp.Choices[0].Message.Content, nil
+	// 5. 校验题目，不合格的题目要求模型修正（修正后合格的题目一并计入本次生成调用）
+	valid, rejected := validateBatch(aiQuestions, req.QuestionType, make(map[string]bool))
+	if len(rejected) > 0 && cfg.AIMaxReprompts > 0 {
+		titles := make(map[string]bool)
+		for _, aq := range valid {
+			titles[titleKey(aq.Title)] = true
+		}
+		var fixed []aiQuestion
+		fixed, rejected = repromptRejected(ctx, provider, call, req, rejected, titles, cfg.AIMaxReprompts)
+		valid = append(valid, fixed...)
+	}
+	call.countQuestions(ctx, len(valid))
+	if len(valid) == 0 {
+		return nil, errNoValidQuestions(parseErrs, rejected)
+	}
+	if !call.cacheHit() {
+		cache.put(ctx, aiResp.Content) // 生成出有效题目的输出才写入缓存
+	}
 
-	default:
-		return "", errors.New("不支持的AI模型")
+	// 6. 转换为数据库模型
+	batch := &generatedBatch{ParseErrors: parseErrs, Rejected: rejected, Cached: call.cacheHit()}
+	for i, aq := range valid {
+		question := toTempQuestion(aq, req, model, previewID, userID, i)
+		question.TemplateVersion = version
+		batch.Questions = append(batch.Questions, question)
 	}
+	return batch, nil
 }
 
-// parseAIResponse 解析AI返回的JSON为TempQuestion
-func parseAIResponse(aiResp string, req GenerateQuestionRequest, previewID string, userID int64) ([]models.TempQuestion, error) {
-	// 定义AI响应结构体
-	type aiQuestion struct {
-		Title       string   `json:"title"`
-		Options     []string `json:"options"`
-		Answer      string   `json:"answer"`
-		Explanation string   `json:"explanation,omitempty"`
+// withMockFailure 附加请求指定的模拟故障（只有 mock 模型读取，见 ai.WithMockFailure）
+func withMockFailure(ctx context.Context, req GenerateQuestionRequest) context.Context {
+	if req.MockFailure == "" {
+		return ctx
 	}
+	return ai.WithMockFailure(ctx, req.MockFailure)
+}
 
-	var aiQuestions []aiQuestion
-	if err := json.Unmarshal([]byte(aiResp), &aiQuestions); err != nil {
-		return nil, fmt.Errorf("JSON解析失败：%w，响应内容：%s", err, aiResp)
-	}
+// aiQuestion AI返回的单道题目结构
+// Answer 的格式因题型而异（字符串、布尔值、数组或对象），校验通过后统一为存储格式的字符串
+type aiQuestion struct {
+	Title        string          `json:"title"`
+	CodeSnippet  string          `json:"code_snippet,omitempty"`
+	CodeLanguage string          `json:"code_language,omitempty"`
+	Options      []string        `json:"options"`
+	Answer       json.RawMessage `json:"answer"`
+	Explanation  string          `json:"explanation,omitempty"`
+	SourceLines  json.RawMessage `json:"source_lines,omitempty"` // 按资料出题时题目依据的行号范围（如 "12-18"）
+}
 
-	// 转换为数据库模型
-	var tempQuestions []models.TempQuestion
-	for i, aq := range aiQuestions {
-		optionsJSON, _ := json.Marshal(aq.Options)
-		tempQuestions = append(tempQuestions, models.TempQuestion{
-			PreviewID:    previewID,
-			TempID:       fmt.Sprintf("%s_%d", previewID, i),
-			UserID:       userID,
-			Title:        aq.Title,
-			QuestionType: req.QuestionType,
-			Options:      string(optionsJSON),
-			Answer:       aq.Answer,
-			Explanation:  aq.Explanation,
-			Keywords:     strings.Join(req.Keywords, ","),
-			Language:     req.Language,
-			AiModel:      req.AIModel,
-		})
+// answerText 取出校验后题目的答案字符串
+func (aq aiQuestion) answerText() string {
+	var answer string
+	if json.Unmarshal(aq.Answer, &answer) != nil {
+		return string(aq.Answer)
 	}
+	return answer
+}
 
-	return tempQuestions, nil
+// generateParams 临时题目保存的生成参数（重新生成单道题目时还原原请求；keywords 列只用于展示和检索）
+type generateParams struct {
+	Keywords []string `json:"keywords"` // 关键词
+	Fallback []string `json:"fallback"` // 降级模型列表（null 表示使用服务端默认配置，空数组表示禁用降级）
+}
+
+// toTempQuestion 将AI返回的单道题目转换为临时题目模型
+func toTempQuestion(aq aiQuestion, req GenerateQuestionRequest, model, previewID string, userID int64, index int) models.TempQuestion {
+	optionsJSON, _ := json.Marshal(aq.Options)
+	paramsJSON, _ := json.Marshal(generateParams{Keywords: req.Keywords, Fallback: req.Fallback})
+	if aq.CodeSnippet != "" && aq.CodeLanguage == "" {
+		aq.CodeLanguage = strings.ToLower(req.Language) // 未标记语言的代码片段默认使用生成语言
+	}
+	temp := models.TempQuestion{
+		PreviewID:    previewID,
+		TempID:       fmt.Sprintf("%s_%d", previewID, index),
+		UserID:       userID,
+		Title:        aq.Title,
+		QuestionType: req.QuestionType,
+		Options:      string(optionsJSON),
+		Answer:       aq.answerText(),
+		CodeSnippet:  aq.CodeSnippet,
+		CodeLanguage: aq.CodeLanguage,
+		Explanation:  aq.Explanation,
+		Keywords:     strings.Join(req.Keywords, ","),
+		Language:     req.Language,
+		AiModel:      model,
+		Difficulty:   req.Difficulty,
+
+		GenerateParams:     string(paramsJSON),
+		VerificationStatus: models.VerificationUnverified,
+	}
+	if req.source != nil {
+		req.source.applyTo(&temp, aq.SourceLines)
+	}
+	return temp
 }
 
 // saveTempQuestions 保存临时题目到数据库
@@ -290,67 +393,197 @@ func saveTempQuestions(ctx context.Context, questions []models.TempQuestion) err
 
 // ConfirmQuestionsRequest 确认题目入库的请求参数
 type ConfirmQuestionsRequest struct {
-	PreviewID string                 `json:"preview_id" binding:"required"` // 预览批次ID
-	Selected  []SelectedTempQuestion `json:"selected" binding:"min=1"`      // 选中的临时题目（至少1道）
+	PreviewID          string                 `json:"preview_id" binding:"required"`                      // 预览批次ID
+	Selected           []SelectedTempQuestion `json:"selected" binding:"min=1"`                           // 选中的临时题目（至少1道）
+	RejectDuplicates   bool                   `json:"reject_duplicates"`                                  // 是否跳过与题库重复的题目
+	DuplicateThreshold float64                `json:"duplicate_threshold" binding:"omitempty,gt=0,lte=1"` // 重复判定阈值（可选，默认使用服务端配置）
 }
 
 // SelectedTempQuestion 选中的单道临时题目（支持编辑）
 type SelectedTempQuestion struct {
-	TempID      string `json:"temp_id" binding:"required"` // 临时题ID
-	Title       string `json:"title,omitempty"`            // 编辑后的标题（可选）
-	Options     string `json:"options,omitempty"`          // 编辑后的选项（可选）
-	Answer      string `json:"answer,omitempty"`           // 编辑后的答案（可选）
-	Explanation string `json:"explanation,omitempty"`      // 编辑后的解析（可选）
+	TempID string `json:"temp_id" binding:"required"` // 临时题ID
+	TempQuestionEdit
+}
+
+// TempQuestionEdit 临时题目的编辑内容（为空的字段保持不变）
+type TempQuestionEdit struct {
+	Title        string `json:"title,omitempty"`         // 编辑后的标题（可选）
+	Options      string `json:"options,omitempty"`       // 编辑后的选项（可选）
+	Answer       string `json:"answer,omitempty"`        // 编辑后的答案（可选）
+	CodeSnippet  string `json:"code_snippet,omitempty"`  // 编辑后的代码片段（可选）
+	CodeLanguage string `json:"code_language,omitempty"` // 编辑后的代码语言标记（可选）
+	Explanation  string `json:"explanation,omitempty"`   // 编辑后的解析（可选）
+	Difficulty   string `json:"difficulty,omitempty"`    // 编辑后的难度（可选）
 }
 
 // ConfirmQuestionsResponse 确认入库的响应数据
 type ConfirmQuestionsResponse struct {
-	QuestionIDs []int64 `json:"question_ids"` // 成功入库的正式题目ID
-	Count       int     `json:"count"`        // 入库数量
+	QuestionIDs      []int64          `json:"question_ids"`                // 入库的正式题目ID（按选中顺序，含此前已确认的题目）
+	Count            int              `json:"count"`                       // 入库数量
+	AlreadyConfirmed int              `json:"already_confirmed,omitempty"` // 此前已确认入库的题目数量（返回原有题目ID，不重复入库）
+	Duplicates       []DuplicateMatch `json:"duplicates,omitempty"`        // 因与题库重复而跳过的题目（仍保留在预览中）
 }
 
-// ConfirmQuestions 确认临时题目并入库
+// ConfirmQuestions 确认临时题目并入库。
+// 正式题目入库和临时题目的软删除在同一个事务中完成；已确认过的题目直接返回原有的正式题目ID，不重复入库。
+// duplicateThreshold > 0 时跳过与题库（含本次先入库的题目）相似度达到阈值的题目；
+// 题目生成超过 previewTTL 时返回 utils.ErrPreviewExpired，同一道题目被重复选中时返回 utils.ErrDuplicateSelection
 func ConfirmQuestions(
 	ctx context.Context,
 	previewID string,
 	selected []SelectedTempQuestion,
 	userID int64,
+	duplicateThreshold float64,
+	previewTTL time.Duration,
 ) (ConfirmQuestionsResponse, error) {
-	// 1. 提取选中的temp_id列表
+	// 1. 提取选中的temp_id列表（同一道题目不能重复选中，否则编辑内容无法确定）
 	tempIDs := make([]string, len(selected))
+	seen := make(map[string]bool, len(selected))
 	for i, s := range selected {
+		if seen[s.TempID] {
+			return ConfirmQuestionsResponse{}, fmt.Errorf("%w：%s", utils.ErrDuplicateSelection, s.TempID)
+		}
+		seen[s.TempID] = true
 		tempIDs[i] = s.TempID
 	}
 
-	// 2. 查询用户的临时题目（验证权限和存在性）
+	// 2. 查询用户的临时题目（含已确认和已过期的，验证权限和存在性）
 	tempQuestions, err := queryTempQuestions(ctx, previewID, tempIDs, userID)
 	if err != nil {
 		return ConfirmQuestionsResponse{}, err
 	}
-	if len(tempQuestions) != len(selected) {
-		return ConfirmQuestionsResponse{}, errors.New("部分临时题目不存在或不属于当前用户")
+	confirmed := make(map[string]int64) // 此前已确认的题目：temp_id -> 正式题目ID
+	var pending []models.TempQuestion
+	for _, temp := range tempQuestions {
+		switch {
+		case temp.QuestionID != nil:
+			confirmed[temp.TempID] = *temp.QuestionID
+		case previewExpired(&temp, previewTTL):
+			return ConfirmQuestionsResponse{}, utils.ErrPreviewExpired
+		case !temp.DeletedAt.Valid: // 已丢弃的题目视为不存在
+			pending = append(pending, temp)
+		}
+	}
+	if len(pending)+len(confirmed) != len(selected) {
+		return ConfirmQuestionsResponse{}, utils.ErrTempQuestionsMissing
 	}
 
-	// 3. 转换为正式题目并入库
-	questionIDs, err := migrateToFormalTable(ctx, tempQuestions, selected, userID)
+	// 3. 转换为正式题目（应用编辑内容并校验）
+	formalQuestions, err := buildFormalQuestions(pending, selected, userID)
 	if err != nil {
 		return ConfirmQuestionsResponse{}, fmt.Errorf("入库失败：%w", err)
 	}
 
-	// 4. 软删除临时表中已确认的记录
-	if err := softDeleteTempQuestions(ctx, tempIDs, userID); err != nil {
-		log.Printf("警告：临时题目软删除失败，temp_ids=%v, err=%v", tempIDs, err)
+	// 4. 按需跳过与题库重复的题目
+	var duplicates []DuplicateMatch
+	if duplicateThreshold > 0 && len(formalQuestions) > 0 {
+		formalQuestions, pending, duplicates, err = filterDuplicates(ctx, userID, formalQuestions, pending, duplicateThreshold)
+		if err != nil {
+			return ConfirmQuestionsResponse{}, err
+		}
+	}
+
+	// 5. 在同一个事务中入库，并将临时题目标记为已确认
+	alreadyConfirmed := len(confirmed)
+	if len(formalQuestions) > 0 {
+		if err := saveConfirmedQuestions(ctx, formalQuestions, pending); err != nil {
+			return ConfirmQuestionsResponse{}, err
+		}
+		for i, temp := range pending {
+			confirmed[temp.TempID] = formalQuestions[i].ID
+		}
 	}
 
+	// 6. 按选中顺序返回正式题目ID（跳过的重复题目除外）
+	questionIDs := make([]int64, 0, len(confirmed))
+	for _, s := range selected {
+		if id, ok := confirmed[s.TempID]; ok {
+			questionIDs = append(questionIDs, id)
+		}
+	}
 	return ConfirmQuestionsResponse{
-		QuestionIDs: questionIDs,
-		Count:       len(questionIDs),
+		QuestionIDs:      questionIDs,
+		Count:            len(questionIDs),
+		AlreadyConfirmed: alreadyConfirmed,
+		Duplicates:       duplicates,
 	}, nil
 }
 
-// 查询临时题目
+// saveConfirmedQuestions 在同一个事务中保存正式题目、软删除对应的临时题目（记录正式题目ID）并删除其历史版本。
+// 临时题目已被其他请求确认或丢弃时回滚，返回 utils.ErrConfirmConflict
+func saveConfirmedQuestions(ctx context.Context, formalQuestions []*models.Question, tempQuestions []models.TempQuestion) error {
+	return dao.Q.Transaction(func(tx *dao.Query) error {
+		// 1. 保存正式题目
+		if err := tx.Question.WithContext(ctx).Create(formalQuestions...); err != nil {
+			return fmt.Errorf("入库失败：%w", err)
+		}
+
+		// 2. 软删除临时题目并记录对应的正式题目ID（只更新仍未删除的记录）
+		t := tx.TempQuestion
+		now := time.Now()
+		recordIDs := make([]int64, len(tempQuestions))
+		for i, temp := range tempQuestions {
+			info, err := t.WithContext(ctx).
+				Where(t.ID.Eq(temp.ID)).
+				UpdateSimple(t.QuestionID.Value(formalQuestions[i].ID), t.DeletedAt.Value(gorm.DeletedAt{Time: now, Valid: true}))
+			if err != nil {
+				return fmt.Errorf("更新临时题目失败：%w", err)
+			}
+			if info.RowsAffected == 0 {
+				return utils.ErrConfirmConflict
+			}
+			recordIDs[i] = temp.ID
+		}
+
+		// 3. 删除已确认题目的历史版本
+		r := tx.TempQuestionRevision
+		if _, err := r.WithContext(ctx).Where(r.TempQuestionID.In(recordIDs...)).Delete(); err != nil {
+			return fmt.Errorf("删除临时题目历史版本失败：%w", err)
+		}
+		return nil
+	})
+}
+
+// filterDuplicates 过滤与题库重复的题目，返回保留的正式题目及对应的临时题目
+func filterDuplicates(
+	ctx context.Context,
+	userID int64,
+	formalQuestions []*models.Question,
+	tempQuestions []models.TempQuestion,
+	threshold float64,
+) ([]*models.Question, []models.TempQuestion, []DuplicateMatch, error) {
+	indexes := make(map[string]*similarityIndex) // 按编程语言加载题库
+	var keptQuestions []*models.Question
+	var keptTemps []models.TempQuestion
+	var duplicates []DuplicateMatch
+	for i, q := range formalQuestions {
+		index, ok := indexes[q.Language]
+		if !ok {
+			var err error
+			if index, err = loadSimilarityIndex(ctx, userID, q.Language); err != nil {
+				return nil, nil, nil, err
+			}
+			indexes[q.Language] = index
+		}
+
+		if best, score := index.closest(questionText(q.Title, q.CodeSnippet, q.Options)); best != nil && score >= threshold {
+			duplicates = append(duplicates, DuplicateMatch{
+				TempID:            tempQuestions[i].TempID,
+				SimilarQuestionID: best.ID,
+				Similarity:        score,
+			})
+			continue
+		}
+		index.add(q)
+		keptQuestions = append(keptQuestions, q)
+		keptTemps = append(keptTemps, tempQuestions[i])
+	}
+	return keptQuestions, keptTemps, duplicates, nil
+}
+
+// 查询临时题目（包括已软删除的，由调用方区分已确认、已过期和已丢弃的题目）
 func queryTempQuestions(ctx context.Context, previewID string, tempIDs []string, userID int64) ([]models.TempQuestion, error) {
-	tempQuestionPtrs, err := dao.Q.TempQuestion.WithContext(ctx).
+	tempQuestionPtrs, err := dao.Q.TempQuestion.WithContext(ctx).Unscoped().
 		Where(
 			dao.Q.TempQuestion.PreviewID.Eq(previewID),
 			dao.Q.TempQuestion.TempID.In(tempIDs...),
@@ -370,85 +603,106 @@ func queryTempQuestions(ctx context.Context, previewID string, tempIDs []string,
 	return tempQuestions, nil
 }
 
-// 迁移到正式表
-func migrateToFormalTable(
-	ctx context.Context,
+// 转换为正式题目（与临时题目一一对应）
+func buildFormalQuestions(
 	tempQuestions []models.TempQuestion,
 	selected []SelectedTempQuestion,
 	userID int64,
-) ([]int64, error) {
+) ([]*models.Question, error) {
 	// 建立temp_id到编辑内容的映射
-	editMap := make(map[string]SelectedTempQuestion)
+	editMap := make(map[string]TempQuestionEdit)
 	for _, s := range selected {
-		editMap[s.TempID] = s
+		editMap[s.TempID] = s.TempQuestionEdit
 	}
 
 	// 转换为正式题目模型
 	var formalQuestions []*models.Question
 	for _, temp := range tempQuestions {
-		edit := editMap[temp.TempID]
-
 		// 优先使用编辑后的数据，否则用临时表原始数据
-		title := temp.Title
-		if edit.Title != "" {
-			title = edit.Title
+		edited, err := applyTempQuestionEdit(temp, editMap[temp.TempID])
+		if err != nil {
+			return nil, err
 		}
 
-		options := temp.Options
-		if edit.Options != "" {
-			options = edit.Options
-			// 校验选项是否为合法JSON
-			if !isValidJSON(options) {
-				return nil, fmt.Errorf("题目[%s]选项格式错误（非JSON）", temp.TempID)
-			}
+		formalQuestions = append(formalQuestions, &models.Question{
+			Title:        edited.Title,
+			QuestionType: edited.QuestionType,
+			Options:      edited.Options,
+			Answer:       edited.Answer,
+			CodeSnippet:  edited.CodeSnippet,
+			CodeLanguage: edited.CodeLanguage,
+			Explanation:  edited.Explanation,
+			Keywords:     edited.Keywords,
+			Language:     edited.Language,
+			AiModel:      edited.AiModel,
+			Difficulty:   edited.Difficulty,
+			UserID:       userID,
+
+			VerificationStatus: edited.VerificationStatus,
+			SourceID:           edited.SourceID,
+			SourcePath:         edited.SourcePath,
+			SourceStartLine:    edited.SourceStartLine,
+			SourceEndLine:      edited.SourceEndLine,
+		})
+	}
+
+	return formalQuestions, nil
+}
+
+// applyTempQuestionEdit 将编辑内容应用到临时题目（为空的字段保持不变），并校验选项、答案和难度。
+// 校验依据（见 reviewedContent）变化后原校验结果不再适用，校验状态重置为 unverified（保留校验模型的答案和理由供参考）
+func applyTempQuestionEdit(temp models.TempQuestion, edit TempQuestionEdit) (models.TempQuestion, error) {
+	original := temp
+	if edit.Title != "" {
+		temp.Title = edit.Title
+	}
+
+	if edit.Options != "" {
+		// 校验选项是否为合法JSON
+		if !isValidJSON(edit.Options) {
+			return temp, fmt.Errorf("题目[%s]选项格式错误（非JSON）", temp.TempID)
 		}
+		temp.Options = edit.Options
+	}
 
+	if edit.Answer != "" || edit.Options != "" {
 		answer := temp.Answer
 		if edit.Answer != "" {
 			answer = edit.Answer
 		}
-
-		explanation := temp.Explanation
-		if edit.Explanation != "" {
-			explanation = edit.Explanation
+		// 按题型校验编辑后的答案
+		normalized, err := normalizeStoredAnswer(temp.QuestionType, answer, temp.Options)
+		if err != nil {
+			return temp, fmt.Errorf("题目[%s]%w", temp.TempID, err)
+		}
+		temp.Answer = normalized
+		if !questionTypes[temp.QuestionType].Choice {
+			temp.Options = "[]" // 非选择题没有选项
 		}
+	}
 
-		formalQuestions = append(formalQuestions, &models.Question{
-			Title:        title,
-			QuestionType: temp.QuestionType,
-			Options:      options,
-			Answer:       answer,
-			Explanation:  explanation,
-			Keywords:     temp.Keywords,
-			Language:     temp.Language,
-			AiModel:      temp.AiModel,
-			UserID:       userID,
-		})
+	if edit.CodeSnippet != "" {
+		temp.CodeSnippet = edit.CodeSnippet
+	}
+	if edit.CodeLanguage != "" {
+		temp.CodeLanguage = edit.CodeLanguage
+	}
+	if edit.Explanation != "" {
+		temp.Explanation = edit.Explanation
 	}
 
-	// 批量插入正式表
-	if err := dao.Q.Question.WithContext(ctx).Create(formalQuestions...); err != nil {
-		return nil, err
+	if edit.Difficulty != "" {
+		if !IsValidDifficulty(edit.Difficulty) {
+			return temp, fmt.Errorf("题目[%s]难度无效（可选 easy/medium/hard）", temp.TempID)
+		}
+		temp.Difficulty = edit.Difficulty
 	}
 
-	// 提取生成的正式题目ID
-	var questionIDs []int64
-	for _, q := range formalQuestions {
-		questionIDs = append(questionIDs, q.ID)
+	if tempReviewedContent(temp) != tempReviewedContent(original) {
+		temp.VerificationStatus = models.VerificationUnverified
 	}
-	return questionIDs, nil
-}
 
-// 软删除临时题目（GORM软删除会自动更新deleted_at字段）
-func softDeleteTempQuestions(ctx context.Context, tempIDs []string, userID int64) error {
-	// 按temp_id和user_id删除（确保只删除当前用户的记录）
-	_, err := dao.Q.TempQuestion.WithContext(ctx).
-		Where(
-			dao.Q.TempQuestion.TempID.In(tempIDs...),
-			dao.Q.TempQuestion.UserID.Eq(userID),
-		).
-		Delete()
-	return err
+	return temp, nil
 }
 
 // 辅助函数：校验JSON格式
@@ -470,8 +724,14 @@ type QuestionItem struct {
 	QuestionType string    `json:"question_type"` // 题型
 	Language     string    `json:"language"`      // 编程语言
 	AiModel      string    `json:"ai_model"`      // AI模型
+	Difficulty   string    `json:"difficulty"`    // 难度
 	Keywords     string    `json:"keywords"`      // 关键词
 	CreatedAt    time.Time `json:"created_at"`    // 创建时间
+
+	VerificationStatus string `json:"verification_status"`         // 答案校验状态
+	SourcePath         string `json:"source_path,omitempty"`       // 出处的文件路径或资料名称（按资料出题时）
+	SourceStartLine    int    `json:"source_start_line,omitempty"` // 出处的起始行号
+	SourceEndLine      int    `json:"source_end_line,omitempty"`   // 出处的结束行号
 }
 
 // Pagination 分页信息
@@ -488,7 +748,10 @@ type GetQuestionsRequest struct {
 	PageSize     int    `form:"page_size"`     // 每页条数
 	Language     string `form:"language"`      // 编程语言筛选
 	QuestionType string `form:"question_type"` // 题型筛选
+	Difficulty   string `form:"difficulty"`    // 难度筛选
 	Sort         string `form:"sort"`          // 排序方式
+
+	VerificationStatus string `form:"verification_status" binding:"omitempty,oneof=unverified verified flagged"` // 答案校验状态筛选
 }
 
 // GetUserQuestions 查询用户的题目列表（带筛选、分页、排序）
@@ -509,13 +772,30 @@ func GetUserQuestions(
 	// 3. 筛选条件：题型
 	if req.QuestionType != "" {
 		// 校验题型合法性
-		if req.QuestionType != "single" && req.QuestionType != "multiple" {
+		if !IsValidQuestionType(req.QuestionType) {
 			return QuestionListResponse{}, errors.New("无效的题型")
 		}
 		query = query.Where(dao.Q.Question.QuestionType.Eq(req.QuestionType))
 	}
 
-	// 4. 排序
+	// 4. 筛选条件：难度
+	if req.Difficulty != "" {
+		if !IsValidDifficulty(req.Difficulty) {
+			return QuestionListResponse{}, errors.New("无效的难度")
+		}
+		query = query.Where(dao.Q.Question.Difficulty.Eq(req.Difficulty))
+	}
+
+	// 5. 筛选条件：答案校验状态
+	if req.VerificationStatus != "" {
+		if !IsValidVerificationStatus(req.VerificationStatus) {
+			return QuestionListResponse{}, errors.New("无效的校验状态")
+		}
+		query = query.Where(dao.Q.Question.VerificationStatus.Eq(req.VerificationStatus))
+	}
+	countQuery := query // 排序和分页前的查询（筛选条件相同），用于统计总条数
+
+	// 6. 排序
 	switch req.Sort {
 	case "created_at_asc":
 		query = query.Order(dao.Q.Question.CreatedAt.Asc())
@@ -525,25 +805,23 @@ func GetUserQuestions(
 		return QuestionListResponse{}, errors.New("无效的排序方式")
 	}
 
-	// 5. 分页计算
+	// 7. 分页计算
 	offset := (req.Page - 1) * req.PageSize
 	query = query.Limit(req.PageSize).Offset(offset)
 
-	// 6. 执行查询
+	// 8. 执行查询
 	questions, err := query.Find()
 	if err != nil {
 		return QuestionListResponse{}, fmt.Errorf("查询失败：%w", err)
 	}
 
-	// 7. 查询总条数（用于分页信息）
-	total, err := dao.Q.Question.WithContext(ctx).
-		Where(dao.Q.Question.UserID.Eq(userID)).
-		Count()
+	// 9. 查询符合筛选条件的总条数（用于分页信息）
+	total, err := countQuery.Count()
 	if err != nil {
 		return QuestionListResponse{}, fmt.Errorf("统计总数失败：%w", err)
 	}
 
-	// 8. 转换响应格式（只返回需要的字段，避免敏感信息）
+	// 10. 转换响应格式（只返回需要的字段，避免敏感信息）
 	var questionList []QuestionItem
 	for _, q := range questions {
 		questionList = append(questionList, QuestionItem{
@@ -552,12 +830,18 @@ func GetUserQuestions(
 			QuestionType: q.QuestionType,
 			Language:     q.Language,
 			AiModel:      q.AiModel,
+			Difficulty:   q.Difficulty,
 			Keywords:     q.Keywords,
-			CreatedAt:    q.CreatedAt,
+
+			VerificationStatus: q.VerificationStatus,
+			SourcePath:         q.SourcePath,
+			SourceStartLine:    q.SourceStartLine,
+			SourceEndLine:      q.SourceEndLine,
+			CreatedAt:          q.CreatedAt,
 		})
 	}
 
-	// 9. 计算总页数
+	// 11. 计算总页数
 	totalPages := (int(total) + req.PageSize - 1) / req.PageSize
 
 	return QuestionListResponse{
@@ -577,8 +861,13 @@ type UpdateQuestionRequest struct {
 	QuestionType string `json:"question_type,omitempty"`
 	Options      string `json:"options,omitempty"`
 	Answer       string `json:"answer,omitempty"`
+	CodeSnippet  string `json:"code_snippet,omitempty"`
+	CodeLanguage string `json:"code_language,omitempty"`
 	Explanation  string `json:"explanation,omitempty"`
 	Keywords     string `json:"keywords,omitempty"`
+	Difficulty   string `json:"difficulty,omitempty"`
+
+	VerificationStatus string `json:"verification_status,omitempty" binding:"omitempty,oneof=unverified verified flagged"` // 人工复核后的校验状态（未传时，题干、代码、选项或答案变化会重置为 unverified）
 }
 
 // UpdateQuestionResponse 题目更新响应
@@ -594,7 +883,7 @@ func UpdateQuestion(
 	req UpdateQuestionRequest,
 ) (UpdateQuestionResponse, error) {
 	// 1. 先查询题目是否存在且属于当前用户
-	_, err := dao.Q.Question.WithContext(ctx).
+	question, err := dao.Q.Question.WithContext(ctx).
 		Where(
 			dao.Q.Question.ID.Eq(questionID),
 			dao.Q.Question.UserID.Eq(userID),
@@ -622,7 +911,7 @@ func UpdateQuestion(
 		updates["title"] = req.Title
 	}
 	if req.QuestionType != "" {
-		if req.QuestionType != "single" && req.QuestionType != "multiple" {
+		if !IsValidQuestionType(req.QuestionType) {
 			return UpdateQuestionResponse{}, errors.New("无效的题型")
 		}
 		updates["question_type"] = req.QuestionType
@@ -633,8 +922,35 @@ func UpdateQuestion(
 		}
 		updates["options"] = req.Options
 	}
-	if req.Answer != "" {
-		updates["answer"] = req.Answer
+	if req.QuestionType != "" || req.Options != "" || req.Answer != "" {
+		// 题型、选项或答案变化时，按修改后的题型重新校验答案
+		questionType, options, answer := question.QuestionType, question.Options, question.Answer
+		if req.QuestionType != "" {
+			questionType = req.QuestionType
+		}
+		if req.Options != "" {
+			options = req.Options
+		}
+		if req.Answer != "" {
+			answer = req.Answer
+		}
+		normalized, err := normalizeStoredAnswer(questionType, answer, options)
+		if err != nil {
+			return UpdateQuestionResponse{}, err
+		}
+		updates["answer"] = normalized
+		if !questionTypes[questionType].Choice {
+			updates["options"] = "[]" // 非选择题没有选项
+		}
+		if questionTypes[questionType].Code && req.CodeSnippet == "" && question.CodeSnippet == "" {
+			return UpdateQuestionResponse{}, fmt.Errorf("%s必须附带代码片段", questionTypeName(questionType))
+		}
+	}
+	if req.CodeSnippet != "" {
+		updates["code_snippet"] = req.CodeSnippet
+	}
+	if req.CodeLanguage != "" {
+		updates["code_language"] = req.CodeLanguage
 	}
 	if req.Explanation != "" {
 		updates["explanation"] = req.Explanation
@@ -642,6 +958,34 @@ func UpdateQuestion(
 	if req.Keywords != "" {
 		updates["keywords"] = req.Keywords
 	}
+	if req.Difficulty != "" {
+		if !IsValidDifficulty(req.Difficulty) {
+			return UpdateQuestionResponse{}, errors.New("无效的难度")
+		}
+		updates["difficulty"] = req.Difficulty
+	}
+	// 校验依据（见 reviewedContent）变化后原校验结果不再适用，人工复核的校验状态优先
+	edited := *question
+	for field, value := range map[string]*string{
+		"question_type": &edited.QuestionType,
+		"title":         &edited.Title,
+		"code_snippet":  &edited.CodeSnippet,
+		"options":       &edited.Options,
+		"answer":        &edited.Answer,
+	} {
+		if v, ok := updates[field].(string); ok {
+			*value = v
+		}
+	}
+	if questionReviewedContent(edited) != questionReviewedContent(*question) {
+		updates["verification_status"] = models.VerificationUnverified
+	}
+	if req.VerificationStatus != "" {
+		if !IsValidVerificationStatus(req.VerificationStatus) {
+			return UpdateQuestionResponse{}, errors.New("无效的校验状态")
+		}
+		updates["verification_status"] = req.VerificationStatus
+	}
 
 	// 3. 执行更新
 	_, err = dao.Q.Question.WithContext(ctx).
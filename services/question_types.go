@@ -0,0 +1,345 @@
+package services
+
+import (
+	"CodeQuizAI/models"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// questionTypeSpec 题型定义：提示语中的输出格式、答案要求以及答案的解析和校验
+type questionTypeSpec struct {
+	Name   string   // 中文名称
+	Choice bool     // 是否为选择题（需要 A、B、C... 选项）
+	Code   bool     // 是否必须附带代码片段
+	Schema string   // 输出格式示例
+	Rules  []string // 答案要求（修正提示中使用）
+	// parseAnswer 解析并校验AI返回的答案，返回存储格式的答案和未通过的校验项
+	parseAnswer func(raw json.RawMessage, options []string) (string, []string)
+	// ReviewRule 校验模型独立作答时的答案格式要求（为空表示该题型不做答案校验）
+	ReviewRule string
+	// matchAnswer 比较校验模型的答案（存储格式）是否与生成的答案一致
+	matchAnswer func(expected, actual string) bool
+}
+
+// questionTypeOrder 支持的题型（按展示顺序）
+var questionTypeOrder = []string{
+	models.QuestionTypeSingle,
+	models.QuestionTypeMultiple,
+	models.QuestionTypeTrueFalse,
+	models.QuestionTypeFillBlank,
+	models.QuestionTypeShortAnswer,
+	models.QuestionTypeCodeOutput,
+}
+
+var questionTypes = map[string]*questionTypeSpec{
+	models.QuestionTypeSingle: {
+		Name:   "单选题",
+		Choice: true,
+		Schema: `[
+  {"title":"...","options":["A. ...","B. ..."],"answer":"A","explanation":"..."}
+]`,
+		Rules:       []string{"答案只能使用已有选项的字母，单选题只有1个答案（如\"A\"）"},
+		parseAnswer: choiceAnswer(false),
+		ReviewRule:  "answer 为正确选项的字母（如\"A\"）",
+		matchAnswer: exactAnswerMatch,
+	},
+	models.QuestionTypeMultiple: {
+		Name:   "多选题",
+		Choice: true,
+		Schema: `[
+  {"title":"...","options":["A. ...","B. ..."],"answer":"AB","explanation":"..."}
+]`,
+		Rules:       []string{"答案只能使用已有选项的字母，多选题至少2个答案（如\"AB\"）"},
+		parseAnswer: choiceAnswer(true),
+		ReviewRule:  "answer 为所有正确选项的字母（如\"AC\"）",
+		matchAnswer: exactAnswerMatch,
+	},
+	models.QuestionTypeTrueFalse: {
+		Name: "判断题",
+		Schema: `[
+  {"title":"...","answer":true,"explanation":"..."}
+]`,
+		Rules:       []string{"答案为布尔值 true（正确）或 false（错误）"},
+		parseAnswer: trueFalseAnswer,
+		ReviewRule:  "answer 为布尔值 true（正确）或 false（错误）",
+		matchAnswer: exactAnswerMatch,
+	},
+	models.QuestionTypeFillBlank: {
+		Name: "填空题",
+		Schema: `[
+  {"title":"... ____ ...","answer":["答案1","答案2","re:^正则$"],"explanation":"..."}
+]`,
+		Rules: []string{
+			"题目中用 ____ 表示空白处",
+			"答案为可接受答案的字符串数组，以 re: 开头的项按正则表达式匹配且必须是合法的正则",
+		},
+		parseAnswer: fillBlankAnswer,
+		ReviewRule:  "answer 为空白处应填写的内容（字符串）",
+		matchAnswer: fillBlankMatch,
+	},
+	models.QuestionTypeShortAnswer: {
+		Name: "简答题",
+		Schema: `[
+  {"title":"...","answer":{"reference":"参考答案","rubric":["评分要点1","评分要点2"]},"explanation":"..."}
+]`,
+		Rules:       []string{"答案为对象，reference 为参考答案，rubric 为评分要点数组（至少1条）"},
+		parseAnswer: shortAnswerAnswer,
+	},
+	models.QuestionTypeCodeOutput: {
+		Name: "代码输出题",
+		Code: true,
+		Schema: `[
+  {"title":"以下程序的输出是什么？","code_snippet":"完整可运行的程序","code_language":"go","answer":"程序的标准输出","explanation":"..."}
+]`,
+		Rules: []string{
+			"code_snippet 为完整可运行、输出确定的程序，code_language 为代码语言（如 go、python）",
+			"答案为程序运行后的标准输出（字符串，多行用 \\n 分隔）",
+		},
+		parseAnswer: codeOutputAnswer,
+		ReviewRule:  "answer 为程序运行后的标准输出（字符串，多行用 \\n 分隔）",
+		matchAnswer: codeOutputMatch,
+	},
+}
+
+// IsValidQuestionType 检查题型是否支持
+func IsValidQuestionType(questionType string) bool {
+	_, ok := questionTypes[questionType]
+	return ok
+}
+
+// QuestionTypes 返回支持的题型列表
+func QuestionTypes() []string {
+	return questionTypeOrder
+}
+
+// questionTypeName 题型的中文名称
+func questionTypeName(questionType string) string {
+	if spec, ok := questionTypes[questionType]; ok {
+		return spec.Name
+	}
+	return questionType
+}
+
+// ShortAnswer 简答题答案（存储为JSON）
+type ShortAnswer struct {
+	Reference string   `json:"reference"` // 参考答案
+	Rubric    []string `json:"rubric"`    // 评分要点
+}
+
+// fillBlankRegexPrefix 填空题中按正则匹配的答案前缀
+const fillBlankRegexPrefix = "re:"
+
+// trueFalseValues 判断题答案的常见写法
+var trueFalseValues = map[string]string{
+	"true": "true", "false": "false",
+	"t": "true", "f": "false",
+	"yes": "true", "no": "false",
+	"正确": "true", "错误": "false",
+	"对": "true", "错": "false",
+}
+
+// choiceAnswer 选择题答案：字母字符串（"AB"、"a, b"）或字母数组（["A","B"]），
+// 规范化为大写并按字母序排列（如 "c, a" -> "AC"）
+func choiceAnswer(multiple bool) func(raw json.RawMessage, options []string) (string, []string) {
+	return func(raw json.RawMessage, options []string) (string, []string) {
+		var text string
+		var letters []string
+		if json.Unmarshal(raw, &text) != nil {
+			if json.Unmarshal(raw, &letters) != nil {
+				return "", []string{"答案应为选项字母组成的字符串"}
+			}
+			text = strings.Join(letters, "")
+		}
+		letters = strings.Split(strings.ToUpper(answerSeparators.Replace(strings.TrimSpace(text))), "")
+		sort.Strings(letters)
+		answer := strings.Join(letters, "")
+
+		// 1. 答案必须是已有选项的字母，且不重复
+		if answer == "" {
+			return "", []string{"答案为空"}
+		}
+		var problems []string
+		seen := make(map[rune]bool)
+		for _, letter := range answer {
+			if letter < 'A' || int(letter-'A') >= len(options) {
+				problems = append(problems, fmt.Sprintf("答案 %s 不在选项范围内（A-%c）", string(letter), rune('A'+len(options)-1)))
+			} else if seen[letter] {
+				problems = append(problems, fmt.Sprintf("答案 %s 重复", string(letter)))
+			}
+			seen[letter] = true
+		}
+
+		// 2. 答案数量与题型一致：单选题1个，多选题至少2个
+		count := len([]rune(answer))
+		if !multiple && count != 1 {
+			problems = append(problems, fmt.Sprintf("单选题只能有1个答案，实际为 %s", answer))
+		}
+		if multiple && count < 2 {
+			problems = append(problems, fmt.Sprintf("多选题至少要有2个答案，实际为 %s", answer))
+		}
+		return answer, problems
+	}
+}
+
+// trueFalseAnswer 判断题答案：布尔值或常见写法的字符串，规范化为 "true"/"false"
+func trueFalseAnswer(raw json.RawMessage, _ []string) (string, []string) {
+	var value bool
+	if json.Unmarshal(raw, &value) == nil {
+		return fmt.Sprint(value), nil
+	}
+	var text string
+	if json.Unmarshal(raw, &text) != nil {
+		return "", []string{"判断题答案应为 true 或 false"}
+	}
+	answer, ok := trueFalseValues[strings.ToLower(strings.TrimSpace(text))]
+	if !ok {
+		return "", []string{fmt.Sprintf("判断题答案应为 true 或 false，实际为 %s", text)}
+	}
+	return answer, nil
+}
+
+// fillBlankAnswer 填空题答案：可接受答案的数组（单个字符串视为只有一个答案），
+// 以 re: 开头的答案按正则匹配。规范化为JSON数组
+func fillBlankAnswer(raw json.RawMessage, _ []string) (string, []string) {
+	var answers []string
+	if json.Unmarshal(raw, &answers) != nil {
+		var text string
+		if json.Unmarshal(raw, &text) != nil {
+			return "", []string{"填空题答案应为字符串数组"}
+		}
+		answers = []string{text}
+	}
+
+	var problems []string
+	accepted := make([]string, 0, len(answers))
+	for i, answer := range answers {
+		answer = strings.TrimSpace(answer)
+		if answer == "" {
+			problems = append(problems, fmt.Sprintf("第%d个答案为空", i+1))
+			continue
+		}
+		if pattern, ok := strings.CutPrefix(answer, fillBlankRegexPrefix); ok {
+			if _, err := regexp.Compile(pattern); err != nil {
+				problems = append(problems, fmt.Sprintf("第%d个答案不是合法的正则表达式：%v", i+1, err))
+				continue
+			}
+		}
+		accepted = append(accepted, answer)
+	}
+	if len(answers) == 0 {
+		problems = append(problems, "答案为空")
+	}
+
+	answerJSON, _ := json.Marshal(accepted)
+	return string(answerJSON), problems
+}
+
+// shortAnswerAnswer 简答题答案：参考答案和评分要点，规范化为JSON对象
+func shortAnswerAnswer(raw json.RawMessage, _ []string) (string, []string) {
+	var answer ShortAnswer
+	if json.Unmarshal(raw, &answer) != nil {
+		return "", []string{"简答题答案应为包含 reference 和 rubric 的对象"}
+	}
+
+	var problems []string
+	answer.Reference = strings.TrimSpace(answer.Reference)
+	if answer.Reference == "" {
+		problems = append(problems, "参考答案为空")
+	}
+	rubric := make([]string, 0, len(answer.Rubric))
+	for _, point := range answer.Rubric {
+		if point = strings.TrimSpace(point); point != "" {
+			rubric = append(rubric, point)
+		}
+	}
+	if len(rubric) == 0 {
+		problems = append(problems, "评分要点为空")
+	}
+	answer.Rubric = rubric
+
+	answerJSON, _ := json.Marshal(answer)
+	return string(answerJSON), problems
+}
+
+// codeOutputAnswer 代码输出题答案：程序的标准输出，统一换行符并去掉末尾的空白。
+// 模型把输出写成数字、数组等非字符串值时按原文处理（如程序打印的 [1 2 3]）
+func codeOutputAnswer(raw json.RawMessage, _ []string) (string, []string) {
+	var output string
+	if json.Unmarshal(raw, &output) != nil {
+		output = string(raw)
+	}
+	output = strings.TrimRight(strings.ReplaceAll(output, "\r\n", "\n"), " \t\n")
+	if output == "" {
+		return "", []string{"答案为空"}
+	}
+	return output, nil
+}
+
+// exactAnswerMatch 规范化后完全一致（选择题、判断题）
+func exactAnswerMatch(expected, actual string) bool {
+	return expected == actual
+}
+
+// fillBlankMatch 校验模型填写的任一答案被生成的可接受答案接受（忽略大小写，re: 开头的按正则匹配）
+func fillBlankMatch(expected, actual string) bool {
+	var accepted, answers []string
+	if json.Unmarshal([]byte(expected), &accepted) != nil || json.Unmarshal([]byte(actual), &answers) != nil {
+		return false
+	}
+	for _, answer := range answers {
+		for _, candidate := range accepted {
+			if pattern, ok := strings.CutPrefix(candidate, fillBlankRegexPrefix); ok {
+				if matched, _ := regexp.MatchString(pattern, answer); matched {
+					return true
+				}
+			} else if strings.EqualFold(strings.TrimSpace(candidate), strings.TrimSpace(answer)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// codeOutputMatch 程序输出逐行比较（忽略行尾空白）
+func codeOutputMatch(expected, actual string) bool {
+	trimLines := func(output string) string {
+		lines := strings.Split(output, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " \t")
+		}
+		return strings.Join(lines, "\n")
+	}
+	return trimLines(expected) == trimLines(actual)
+}
+
+// storedAnswerJSON 将存储格式的答案还原为AI输出格式（JSON数组或对象原样使用，其余按字符串处理）
+func storedAnswerJSON(answer string) json.RawMessage {
+	trimmed := strings.TrimSpace(answer)
+	if (strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{")) && json.Valid([]byte(trimmed)) {
+		return json.RawMessage(trimmed)
+	}
+	text, _ := json.Marshal(answer)
+	return text
+}
+
+// normalizeStoredAnswer 按题型校验人工编辑的答案（如确认入库和修改题目时），返回存储格式的答案
+func normalizeStoredAnswer(questionType, answer string, options string) (string, error) {
+	spec, ok := questionTypes[questionType]
+	if !ok {
+		return "", fmt.Errorf("不支持的题型：%s", questionType)
+	}
+	var optionList []string
+	if spec.Choice {
+		if err := json.Unmarshal([]byte(options), &optionList); err != nil {
+			return "", fmt.Errorf("选项格式错误：%w", err)
+		}
+	}
+	normalized, problems := spec.parseAnswer(storedAnswerJSON(answer), optionList)
+	if len(problems) > 0 {
+		return "", fmt.Errorf("答案不符合%s的要求：%s", spec.Name, strings.Join(problems, "；"))
+	}
+	return normalized, nil
+}
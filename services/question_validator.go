@@ -0,0 +1,217 @@
+package services
+
+import (
+	"CodeQuizAI/ai"
+	"CodeQuizAI/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// 选择题选项数量范围
+const (
+	minOptions = 2
+	maxOptions = 6
+)
+
+// optionLabelPattern 选项格式："A. 内容"（兼容全角标点和顿号）
+var optionLabelPattern = regexp.MustCompile(`^([A-Z])\s*[.．、:：)）]\s*(.*)$`)
+
+// answerSeparators 答案中允许出现的分隔符（规范化时去掉）
+var answerSeparators = strings.NewReplacer(" ", "", ",", "", "，", "", "、", "", ";", "", "；", "")
+
+// 代码块：```go\n...\n```（语言标记可选）
+var (
+	snippetFencePattern = regexp.MustCompile("(?s)^```([\\w+#.-]*)[ \\t]*\\r?\\n(.*?)\\s*```$")
+	titleCodePattern    = regexp.MustCompile("(?s)```([\\w+#.-]*)[ \\t]*\\r?\\n(.*?)\\s*```")
+)
+
+// RejectedQuestion 校验未通过（且修正后仍不合格）的题目
+type RejectedQuestion struct {
+	aiQuestion
+	Problems []string `json:"problems"` // 未通过的校验项
+}
+
+// normalizeQuestion 规范化题目：去掉首尾空白，非选择题忽略选项；
+// 代码片段去掉 markdown 代码块标记，写在标题中的代码块移到代码片段中
+func normalizeQuestion(aq aiQuestion, spec *questionTypeSpec) aiQuestion {
+	aq.Title = strings.TrimSpace(aq.Title)
+	aq.Explanation = strings.TrimSpace(aq.Explanation)
+	aq.CodeSnippet = strings.Trim(aq.CodeSnippet, "\r\n")
+	aq.CodeLanguage = strings.ToLower(strings.TrimSpace(aq.CodeLanguage))
+	if m := snippetFencePattern.FindStringSubmatch(strings.TrimSpace(aq.CodeSnippet)); m != nil {
+		aq.CodeSnippet = m[2]
+		if aq.CodeLanguage == "" {
+			aq.CodeLanguage = strings.ToLower(m[1])
+		}
+	}
+	if m := titleCodePattern.FindStringSubmatchIndex(aq.Title); m != nil && aq.CodeSnippet == "" {
+		aq.CodeSnippet = aq.Title[m[4]:m[5]]
+		if aq.CodeLanguage == "" {
+			aq.CodeLanguage = strings.ToLower(aq.Title[m[2]:m[3]])
+		}
+		aq.Title = strings.TrimSpace(aq.Title[:m[0]] + " " + aq.Title[m[1]:])
+	}
+	if aq.CodeSnippet == "" {
+		aq.CodeLanguage = ""
+	}
+	options := make([]string, 0, len(aq.Options))
+	if spec == nil || spec.Choice {
+		for _, option := range aq.Options {
+			options = append(options, strings.TrimSpace(option))
+		}
+	}
+	aq.Options = options
+	return aq
+}
+
+// validateQuestion 校验单道题目，返回存储格式的答案和未通过的校验项（为空表示合格）
+func validateQuestion(aq aiQuestion, questionType string) (string, []string) {
+	var problems []string
+
+	// 1. 题型必须受支持
+	spec, ok := questionTypes[questionType]
+	if !ok {
+		return "", []string{fmt.Sprintf("不支持的题型：%s", questionType)}
+	}
+
+	// 2. 标题不能为空
+	if aq.Title == "" {
+		problems = append(problems, "题目标题为空")
+	}
+
+	// 3. 选择题的选项数量在范围内，标签按 A、B、C... 顺序排列，内容非空且不重复
+	if spec.Choice {
+		problems = append(problems, validateOptions(aq.Options)...)
+	}
+
+	// 4. 需要代码的题型必须附带代码片段
+	if spec.Code && strings.TrimSpace(aq.CodeSnippet) == "" {
+		problems = append(problems, fmt.Sprintf("%s缺少代码片段（code_snippet）", spec.Name))
+	}
+
+	// 5. 按题型解析并校验答案
+	if len(aq.Answer) == 0 || string(aq.Answer) == "null" {
+		return "", append(problems, "答案为空")
+	}
+	answer, answerProblems := spec.parseAnswer(aq.Answer, aq.Options)
+	return answer, append(problems, answerProblems...)
+}
+
+// validateOptions 校验选择题选项
+func validateOptions(options []string) []string {
+	var problems []string
+	if len(options) < minOptions || len(options) > maxOptions {
+		problems = append(problems, fmt.Sprintf("选项数量应为%d-%d个，实际为%d个", minOptions, maxOptions, len(options)))
+	}
+
+	contents := make(map[string]bool)
+	for i, option := range options {
+		expected := string(rune('A' + i))
+		m := optionLabelPattern.FindStringSubmatch(option)
+		if m == nil || m[1] != expected {
+			problems = append(problems, fmt.Sprintf("第%d个选项应以\"%s. \"开头：%s", i+1, expected, option))
+			continue
+		}
+		content := strings.TrimSpace(m[2])
+		if content == "" {
+			problems = append(problems, fmt.Sprintf("选项%s内容为空", expected))
+			continue
+		}
+		if contents[content] {
+			problems = append(problems, fmt.Sprintf("选项%s与其他选项内容重复", expected))
+		}
+		contents[content] = true
+	}
+	return problems
+}
+
+// titleKey 标题去重的比较键（忽略大小写和多余空白）
+func titleKey(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
+
+// validateBatch 规范化并校验一批题目，titles 记录本批次已接受的标题（用于去重），合格题目的标题会加入其中
+func validateBatch(questions []aiQuestion, questionType string, titles map[string]bool) (valid []aiQuestion, rejected []RejectedQuestion) {
+	for _, aq := range questions {
+		aq = normalizeQuestion(aq, questionTypes[questionType])
+		answer, problems := validateQuestion(aq, questionType)
+		if aq.Title != "" && titles[titleKey(aq.Title)] {
+			problems = append(problems, "题目标题与本批次其他题目重复")
+		}
+		if len(problems) > 0 {
+			rejected = append(rejected, RejectedQuestion{aiQuestion: aq, Problems: problems})
+			continue
+		}
+		titles[titleKey(aq.Title)] = true
+		aq.Answer, _ = json.Marshal(answer) // 合格题目的答案统一为存储格式的字符串
+		valid = append(valid, aq)
+	}
+	return valid, rejected
+}
+
+// repromptRejected 将校验未通过的题目及原因发回模型修正，最多 maxReprompts 轮。
+// 修正调用按 reprompt 用途记录用量（修正后合格的题目由调用方计入生成调用）。返回修正后合格的题目和仍不合格的题目
+func repromptRejected(
+	ctx context.Context,
+	provider ai.Provider,
+	call *aiCall,
+	req GenerateQuestionRequest,
+	rejected []RejectedQuestion,
+	titles map[string]bool,
+	maxReprompts int,
+) (fixed []aiQuestion, remaining []RejectedQuestion) {
+	remaining = rejected
+	call = call.withPurpose(models.AICallPurposeReprompt)
+	for round := 1; round <= maxReprompts && len(remaining) > 0; round++ {
+		// 1. 请求模型按原顺序返回修正后的题目
+		aiReq := &ai.Request{Prompt: buildRepairPrompt(req, remaining)}
+		aiResp, err := call.chat(withMockFailure(ctx, req), provider, aiReq)
+		if err != nil {
+			log.Printf("AI模型 %s 修正题目失败（第%d轮）: %v", provider.Name(), round, err)
+			break
+		}
+		corrected, _, err := extractAIQuestions(aiResp.Content)
+		if err != nil {
+			log.Printf("AI模型 %s 修正结果解析失败（第%d轮）: %v", provider.Name(), round, err)
+			continue
+		}
+
+		// 2. 逐题重新校验，缺失的题目保留原校验结果
+		var still []RejectedQuestion
+		for i, r := range remaining {
+			if i >= len(corrected) {
+				still = append(still, r)
+				continue
+			}
+			valid, invalid := validateBatch([]aiQuestion{corrected[i]}, req.QuestionType, titles)
+			fixed = append(fixed, valid...)
+			still = append(still, invalid...)
+		}
+		remaining = still
+	}
+	return fixed, remaining
+}
+
+// buildRepairPrompt 构造修正题目的提示语（附带每道题目未通过的校验项）
+func buildRepairPrompt(req GenerateQuestionRequest, rejected []RejectedQuestion) string {
+	spec := questionTypes[req.QuestionType]
+	var b strings.Builder
+	fmt.Fprintf(&b, "以下%d道关于%s语言的%s未通过校验，请逐题修正后按原顺序返回。\n", len(rejected), req.Language, spec.Name)
+	for i, r := range rejected {
+		questionJSON, _ := json.Marshal(r.aiQuestion)
+		fmt.Fprintf(&b, "\n第%d题：%s\n存在的问题：%s\n", i+1, questionJSON, strings.Join(r.Problems, "；"))
+	}
+	b.WriteString("\n要求：\n")
+	if spec.Choice {
+		fmt.Fprintf(&b, "- 选项数量为%d-%d个，依次以\"A. \"、\"B. \"等开头，内容不能为空或重复\n", minOptions, maxOptions)
+	}
+	for _, rule := range spec.Rules {
+		fmt.Fprintf(&b, "- %s\n", rule)
+	}
+	fmt.Fprintf(&b, "- 题目标题不能为空，不能与其他题目重复\n\n严格返回JSON数组，无额外内容：\n%s", spec.Schema)
+	return b.String()
+}
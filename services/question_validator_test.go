@@ -0,0 +1,259 @@
+package services
+
+import (
+	"CodeQuizAI/ai"
+	"CodeQuizAI/config"
+	"CodeQuizAI/dao"
+	"CodeQuizAI/models"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestValidateBatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		questionType string
+		question     string // AI输出的单道题目
+		wantAnswer   string // 合格时存储格式的答案
+		wantProblem  string // 不合格时包含的校验项（为空表示应合格）
+	}{
+		// 单选题
+		{"单选题合格", "single", `{"title":" 题目 ","options":["A. 甲","B. 乙"],"answer":"b"}`, "B", ""},
+		{"单选题答案超出选项", "single", `{"title":"题目","options":["A. 甲","B. 乙"],"answer":"C"}`, "", "不在选项范围内"},
+		{"单选题多个答案", "single", `{"title":"题目","options":["A. 甲","B. 乙"],"answer":"AB"}`, "", "单选题只能有1个答案"},
+		{"选项标签不连续", "single", `{"title":"题目","options":["A. 甲","C. 乙"],"answer":"A"}`, "", `第2个选项应以"B. "开头`},
+		{"选项内容重复", "single", `{"title":"题目","options":["A. 甲","B. 甲"],"answer":"A"}`, "", "与其他选项内容重复"},
+		{"选项数量不足", "single", `{"title":"题目","options":["A. 甲"],"answer":"A"}`, "", "选项数量应为2-6个"},
+		{"标题为空", "single", `{"title":"  ","options":["A. 甲","B. 乙"],"answer":"A"}`, "", "题目标题为空"},
+		{"答案为空", "single", `{"title":"题目","options":["A. 甲","B. 乙"],"answer":null}`, "", "答案为空"},
+
+		// 多选题
+		{"多选题字母数组", "multiple", `{"title":"题目","options":["A. 甲","B. 乙","C. 丙"],"answer":["c","a"]}`, "AC", ""},
+		{"多选题带分隔符", "multiple", `{"title":"题目","options":["A. 甲","B. 乙","C. 丙"],"answer":"B, C"}`, "BC", ""},
+		{"多选题只有一个答案", "multiple", `{"title":"题目","options":["A. 甲","B. 乙"],"answer":"A"}`, "", "多选题至少要有2个答案"},
+		{"多选题答案重复", "multiple", `{"title":"题目","options":["A. 甲","B. 乙"],"answer":"AA"}`, "", "答案 A 重复"},
+
+		// 判断题
+		{"判断题布尔值", "true_false", `{"title":"题目","answer":false}`, "false", ""},
+		{"判断题中文写法", "true_false", `{"title":"题目","answer":"对"}`, "true", ""},
+		{"判断题忽略选项", "true_false", `{"title":"题目","options":["A. 对"],"answer":true}`, "true", ""},
+		{"判断题无法识别", "true_false", `{"title":"题目","answer":"也许"}`, "", "判断题答案应为 true 或 false"},
+
+		// 填空题
+		{"填空题答案数组", "fill_blank", `{"title":"____ 启动协程","answer":[" go ","re:^(?i)go$"]}`, `["go","re:^(?i)go$"]`, ""},
+		{"填空题单个字符串", "fill_blank", `{"title":"____ 启动协程","answer":"go"}`, `["go"]`, ""},
+		{"填空题非法正则", "fill_blank", `{"title":"____ 启动协程","answer":["re:("]}`, "", "不是合法的正则表达式"},
+		{"填空题空答案", "fill_blank", `{"title":"____ 启动协程","answer":["go",""]}`, "", "第2个答案为空"},
+		{"填空题答案数组为空", "fill_blank", `{"title":"____ 启动协程","answer":[]}`, "", "答案为空"},
+
+		// 简答题
+		{"简答题合格", "short_answer", `{"title":"题目","answer":{"reference":" 参考 ","rubric":["要点1"," "]}}`, `{"reference":"参考","rubric":["要点1"]}`, ""},
+		{"简答题缺少评分要点", "short_answer", `{"title":"题目","answer":{"reference":"参考","rubric":[]}}`, "", "评分要点为空"},
+		{"简答题答案不是对象", "short_answer", `{"title":"题目","answer":"参考答案"}`, "", "应为包含 reference 和 rubric 的对象"},
+
+		// 代码输出题
+		{"代码输出题数字答案", "code_output", `{"title":"输出是什么？","code_snippet":"print(1+2)","answer":3}`, "3", ""},
+		{"代码输出题统一换行", "code_output", `{"title":"输出是什么？","code_snippet":"print(1)\nprint(2)","answer":"1\r\n2\n"}`, "1\n2", ""},
+		{"代码写在标题中", "code_output", "{\"title\":\"输出是什么？\\n```python\\nprint(1)\\n```\",\"answer\":\"1\"}", "1", ""},
+		{"代码输出题缺少代码", "code_output", `{"title":"输出是什么？","answer":"1"}`, "", "缺少代码片段"},
+
+		// 其他
+		{"不支持的题型", "essay", `{"title":"题目","answer":"答案"}`, "", "不支持的题型"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var aq aiQuestion
+			if err := json.Unmarshal([]byte(tt.question), &aq); err != nil {
+				t.Fatalf("题目格式错误：%v", err)
+			}
+			valid, rejected := validateBatch([]aiQuestion{aq}, tt.questionType, make(map[string]bool))
+
+			if tt.wantProblem != "" {
+				if len(valid) != 0 || len(rejected) != 1 {
+					t.Fatalf("合格 %d 道，不合格 %d 道，期望不合格", len(valid), len(rejected))
+				}
+				problems := strings.Join(rejected[0].Problems, "；")
+				if !strings.Contains(problems, tt.wantProblem) {
+					t.Errorf("校验项 = %q，期望包含 %q", problems, tt.wantProblem)
+				}
+				return
+			}
+
+			if len(valid) != 1 {
+				t.Fatalf("题目不合格：%+v", rejected)
+			}
+			if got := valid[0].answerText(); got != tt.wantAnswer {
+				t.Errorf("答案 = %q，期望 %q", got, tt.wantAnswer)
+			}
+			if valid[0].Title != strings.TrimSpace(valid[0].Title) {
+				t.Errorf("标题未去掉首尾空白：%q", valid[0].Title)
+			}
+		})
+	}
+}
+
+func TestValidateBatchDuplicateTitles(t *testing.T) {
+	// 标题忽略大小写和多余空白比较；已接受的标题（如其他批次）同样计入
+	titles := map[string]bool{titleKey("已有题目"): true}
+	questions := []aiQuestion{
+		{Title: "Go 的  零值", Options: []string{"A. 甲", "B. 乙"}, Answer: json.RawMessage(`"A"`)},
+		{Title: "go 的 零值", Options: []string{"A. 甲", "B. 乙"}, Answer: json.RawMessage(`"B"`)},
+		{Title: "已有题目", Options: []string{"A. 甲", "B. 乙"}, Answer: json.RawMessage(`"A"`)},
+	}
+	valid, rejected := validateBatch(questions, "single", titles)
+	if len(valid) != 1 || len(rejected) != 2 {
+		t.Fatalf("合格 %d 道，不合格 %d 道，期望 1 和 2", len(valid), len(rejected))
+	}
+	for _, r := range rejected {
+		if !strings.Contains(strings.Join(r.Problems, "；"), "题目标题与本批次其他题目重复") {
+			t.Errorf("%q 的校验项 = %q，期望标题重复", r.Title, r.Problems)
+		}
+	}
+	if !titles[titleKey("Go 的 零值")] {
+		t.Error("合格题目的标题未加入 titles")
+	}
+}
+
+// recordingProvider 记录发给模型的提示语
+type recordingProvider struct {
+	ai.Provider
+	prompts []string
+}
+
+func (p *recordingProvider) Chat(ctx context.Context, req *ai.Request) (*ai.Response, error) {
+	p.prompts = append(p.prompts, req.Prompt)
+	return p.Provider.Chat(ctx, req)
+}
+
+// setupTestDB 使用临时数据库文件作为DAO的默认连接
+func setupTestDB(t *testing.T, tables ...interface{}) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("连接测试数据库失败：%v", err)
+	}
+	if err := db.AutoMigrate(tables...); err != nil {
+		t.Fatalf("创建测试表失败：%v", err)
+	}
+	dao.SetDefault(db)
+}
+
+// setupMockModel 启用离线 mock 模型（不重试）
+func setupMockModel(t *testing.T) ai.Provider {
+	t.Helper()
+	t.Setenv("MOCK_ENABLED", "true")
+	t.Setenv("MOCK_FAILURE", "")
+	if err := ai.Setup(&config.Config{AITimeoutSeconds: 5, AIBreakerThreshold: 100}); err != nil {
+		t.Fatalf("启用 mock 模型失败：%v", err)
+	}
+	provider, err := ai.Get("mock")
+	if err != nil {
+		t.Fatalf("获取 mock 模型失败：%v", err)
+	}
+	return provider
+}
+
+// repairCountPattern 修正提示语中待修正的题目数量
+var repairCountPattern = regexp.MustCompile(`^以下(\d+)道`)
+
+func TestRepromptRejected(t *testing.T) {
+	setupTestDB(t, &models.AICall{})
+	mock := setupMockModel(t)
+
+	// 校验夹具：1道合格，2道不合格（选项不足、答案超出选项）
+	fixture := []aiQuestion{
+		{Title: "Go 中启动协程的关键字是？", Options: []string{"A. go", "B. async"}, Answer: json.RawMessage(`"A"`)},
+		{Title: "选项不足的题目", Options: []string{"A. 只有一个选项"}, Answer: json.RawMessage(`"A"`)},
+		{Title: "答案超出选项的题目", Options: []string{"A. 甲", "B. 乙"}, Answer: json.RawMessage(`"E"`)},
+	}
+
+	tests := []struct {
+		name          string
+		mockFailure   string // 修正调用的模拟故障
+		maxReprompts  int
+		wantCounts    []int // 每轮修正提示语中的题目数量
+		wantFixed     int
+		wantRemaining int
+	}{
+		{name: "一轮全部修正", maxReprompts: 2, wantCounts: []int{2}, wantFixed: 2},
+		{name: "只把仍不合格的题目再次发回", mockFailure: "invalid", maxReprompts: 2, wantCounts: []int{2, 1}, wantFixed: 2},
+		{name: "达到修正轮数上限", mockFailure: "invalid", maxReprompts: 1, wantCounts: []int{2}, wantFixed: 1, wantRemaining: 1},
+		{name: "模型调用失败", mockFailure: "error", maxReprompts: 2, wantCounts: []int{2}, wantRemaining: 2},
+		{name: "不修正", maxReprompts: 0, wantRemaining: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			titles := make(map[string]bool)
+			valid, rejected := validateBatch(fixture, "single", titles)
+			if len(valid) != 1 || len(rejected) != 2 {
+				t.Fatalf("夹具校验结果：合格 %d 道，不合格 %d 道，期望 1 和 2", len(valid), len(rejected))
+			}
+
+			provider := &recordingProvider{Provider: mock}
+			req := GenerateQuestionRequest{Language: "Go", QuestionType: "single", MockFailure: tt.mockFailure}
+			call := newAICall(&config.Config{}, 1, "preview", models.AICallPurposeGenerate)
+			fixed, remaining := repromptRejected(context.Background(), provider, call, req, rejected, titles, tt.maxReprompts)
+
+			// 1. 每轮只发回不合格的题目，合格的题目不再发给模型
+			if len(provider.prompts) != len(tt.wantCounts) {
+				t.Fatalf("修正调用 %d 次，期望 %d 次", len(provider.prompts), len(tt.wantCounts))
+			}
+			for i, prompt := range provider.prompts {
+				m := repairCountPattern.FindStringSubmatch(prompt)
+				if m == nil || m[1] != strconv.Itoa(tt.wantCounts[i]) {
+					t.Errorf("第%d轮修正提示语的题目数量 = %v，期望 %d", i+1, m, tt.wantCounts[i])
+				}
+				if strings.Contains(prompt, valid[0].Title) {
+					t.Errorf("第%d轮修正提示语包含了合格的题目", i+1)
+				}
+			}
+			if len(provider.prompts) > 0 {
+				for _, r := range rejected {
+					if !strings.Contains(provider.prompts[0], r.Title) || !strings.Contains(provider.prompts[0], r.Problems[0]) {
+						t.Errorf("修正提示语缺少不合格的题目或原因：%q", r.Title)
+					}
+				}
+			}
+
+			// 2. 修正后合格的题目通过校验且标题不重复，仍不合格的题目带有原因
+			if len(fixed) != tt.wantFixed || len(remaining) != tt.wantRemaining {
+				t.Fatalf("修正后合格 %d 道，仍不合格 %d 道，期望 %d 和 %d", len(fixed), len(remaining), tt.wantFixed, tt.wantRemaining)
+			}
+			for _, aq := range fixed {
+				if _, problems := validateQuestion(aq, "single"); len(problems) > 0 {
+					t.Errorf("修正后的题目不合格：%q %v", aq.Title, problems)
+				}
+				if !titles[titleKey(aq.Title)] {
+					t.Errorf("修正后合格的标题未加入 titles：%q", aq.Title)
+				}
+			}
+			for _, r := range remaining {
+				if len(r.Problems) == 0 {
+					t.Errorf("仍不合格的题目缺少原因：%q", r.Title)
+				}
+			}
+		})
+	}
+
+	// 3. 修正调用按 reprompt 用途记录
+	calls, err := dao.Q.AICall.WithContext(context.Background()).Find()
+	if err != nil || len(calls) == 0 {
+		t.Fatalf("调用记录 = %d（%v），期望有修正调用的记录", len(calls), err)
+	}
+	for _, c := range calls {
+		if c.Purpose != models.AICallPurposeReprompt {
+			t.Errorf("调用记录的用途 = %q，期望 %q", c.Purpose, models.AICallPurposeReprompt)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"CodeQuizAI/config"
+	"CodeQuizAI/dao"
+	"CodeQuizAI/models"
+	"CodeQuizAI/utils"
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gen"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 配额项名称
+const (
+	QuotaDailyQuestions   = "daily_questions"
+	QuotaMonthlyQuestions = "monthly_questions"
+	QuotaDailyTokens      = "daily_tokens"
+	QuotaMonthlyTokens    = "monthly_tokens"
+)
+
+// 配额来源
+const (
+	quotaSourceRole = "role" // 角色默认配额
+	quotaSourceUser = "user" // 用户单独设置的配额
+)
+
+// QuotaUsage 单项配额的使用情况
+type QuotaUsage struct {
+	Name      string    `json:"name"`      // 配额项（daily_questions/monthly_questions/daily_tokens/monthly_tokens）
+	Limit     int64     `json:"limit"`     // 上限（0 表示不限）
+	Used      int64     `json:"used"`      // 本周期已使用
+	Remaining int64     `json:"remaining"` // 剩余（不限时为 -1）
+	ResetAt   time.Time `json:"reset_at"`  // 下次重置时间
+	Source    string    `json:"source"`    // 来源（role：角色默认，user：单独设置）
+	Reserved  int64     `json:"reserved"`  // 进行中的生成请求预占的数量（题目配额为题目数量，token 配额为预估的 token 数）
+}
+
+// QuotaStatus 用户的配额状态
+type QuotaStatus struct {
+	UserID    int64        `json:"user_id"`
+	Role      string       `json:"role"`
+	Quotas    []QuotaUsage `json:"quotas"`              // 各项配额
+	Requested int          `json:"requested,omitempty"` // 本次请求的题目数量（检查配额时）
+	Exceeded  []string     `json:"exceeded,omitempty"`  // 超出的配额项
+}
+
+// periodUsage 一个周期内的用量
+type periodUsage struct {
+	Questions        int64
+	PromptTokens     int64
+	CompletionTokens int64
+}
+
+// tokens 周期内消耗的 token 总数
+func (u periodUsage) tokens() int64 {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// GetQuotaStatus 查询用户的配额和本日、本月的用量（按服务器本地时间划分周期）
+func GetQuotaStatus(ctx context.Context, userID int64, cfg *config.Config) (*QuotaStatus, error) {
+	return getQuotaStatus(ctx, userID, cfg, 0)
+}
+
+// getQuotaStatus 查询配额状态，预占的数量不计入 excludeID 对应的预占（检查本次请求的预占时使用）
+func getQuotaStatus(ctx context.Context, userID int64, cfg *config.Config, excludeID int64) (*QuotaStatus, error) {
+	// 1. 查询用户角色和单独设置的配额
+	user, err := dao.Q.User.WithContext(ctx).Where(dao.User.ID.Eq(userID)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("查询用户失败：%w", err)
+	}
+	override, err := dao.Q.UserQuota.WithContext(ctx).Where(dao.UserQuota.UserID.Eq(userID)).First()
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询用户配额失败：%w", err)
+	}
+
+	// 2. 统计本日和本月的用量
+	now := time.Now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	daily, err := getPeriodUsage(ctx, userID, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	monthly, err := getPeriodUsage(ctx, userID, monthStart)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 统计进行中的生成请求预占的数量
+	reserved, err := getReservedUsage(ctx, userID, excludeID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 合并角色默认配额和单独设置的配额（扣除预占的数量）
+	defaults := cfg.RoleQuota(user.Role)
+	var dailyQuestions, monthlyQuestions, dailyTokens, monthlyTokens *int64
+	if override != nil {
+		dailyQuestions, monthlyQuestions = override.DailyQuestions, override.MonthlyQuestions
+		dailyTokens, monthlyTokens = override.DailyTokens, override.MonthlyTokens
+	}
+	status := &QuotaStatus{UserID: userID, Role: user.Role}
+	status.Quotas = []QuotaUsage{
+		newQuotaUsage(QuotaDailyQuestions, defaults.DailyQuestions, dailyQuestions, daily.Questions, reserved.Questions, dayStart.AddDate(0, 0, 1)),
+		newQuotaUsage(QuotaMonthlyQuestions, defaults.MonthlyQuestions, monthlyQuestions, monthly.Questions, reserved.Questions, monthStart.AddDate(0, 1, 0)),
+		newQuotaUsage(QuotaDailyTokens, defaults.DailyTokens, dailyTokens, daily.tokens(), reserved.Tokens, dayStart.AddDate(0, 0, 1)),
+		newQuotaUsage(QuotaMonthlyTokens, defaults.MonthlyTokens, monthlyTokens, monthly.tokens(), reserved.Tokens, monthStart.AddDate(0, 1, 0)),
+	}
+	return status, nil
+}
+
+// newQuotaUsage 计算单项配额的使用情况（单独设置的配额优先，剩余量扣除已预占的数量）
+func newQuotaUsage(name string, roleLimit int64, userLimit *int64, used, reserved int64, resetAt time.Time) QuotaUsage {
+	usage := QuotaUsage{Name: name, Limit: roleLimit, Used: used, Reserved: reserved, ResetAt: resetAt, Source: quotaSourceRole}
+	if userLimit != nil {
+		usage.Limit, usage.Source = *userLimit, quotaSourceUser
+	}
+	usage.Remaining = -1
+	if usage.Limit > 0 {
+		usage.Remaining = max(usage.Limit-used-reserved, 0)
+	}
+	return usage
+}
+
+// getPeriodUsage 统计用户自 since 起生成的题目数量和消耗的 token 数
+func getPeriodUsage(ctx context.Context, userID int64, since time.Time) (periodUsage, error) {
+	var usage periodUsage
+	c := dao.AICall
+	err := dao.Q.AICall.WithContext(ctx).
+		Select(c.Questions.Sum().As("questions"), c.PromptTokens.Sum().As("prompt_tokens"), c.CompletionTokens.Sum().As("completion_tokens")).
+		Where(c.UserID.Eq(userID), c.CreatedAt.Gte(since)).
+		Scan(&usage)
+	if err != nil {
+		return usage, fmt.Errorf("统计用量失败：%w", err)
+	}
+	return usage, nil
+}
+
+// reservedUsage 进行中的生成请求预占的数量
+type reservedUsage struct {
+	Questions int64
+	Tokens    int64
+}
+
+// getReservedUsage 统计用户有效的预占：未过期的同步请求预占，以及排队或执行中的异步任务的预占
+// （预占保存在数据库中，多个实例共享，重启后恢复的任务仍持有预占）
+func getReservedUsage(ctx context.Context, userID, excludeID int64, now time.Time) (reservedUsage, error) {
+	r := dao.QuotaReservation
+	var total reservedUsage
+
+	// 1. 未过期的同步请求预占
+	var active reservedUsage
+	if err := dao.Q.QuotaReservation.WithContext(ctx).
+		Select(r.Questions.Sum().As("questions"), r.Tokens.Sum().As("tokens")).
+		Where(r.UserID.Eq(userID), r.ID.Neq(excludeID), r.JobID.IsNull(), r.ExpiresAt.Gt(now)).
+		Scan(&active); err != nil {
+		return total, fmt.Errorf("统计预占配额失败：%w", err)
+	}
+	total = active
+
+	// 2. 排队或执行中的异步任务的预占
+	var jobIDs []int64
+	if err := dao.Q.GenerationJob.WithContext(ctx).
+		Where(dao.GenerationJob.UserID.Eq(userID), dao.GenerationJob.Status.In(models.JobStatusQueued, models.JobStatusRunning)).
+		Pluck(dao.GenerationJob.ID, &jobIDs); err != nil {
+		return total, fmt.Errorf("查询进行中的生成任务失败：%w", err)
+	}
+	if len(jobIDs) == 0 {
+		return total, nil
+	}
+	var jobs reservedUsage
+	if err := dao.Q.QuotaReservation.WithContext(ctx).
+		Select(r.Questions.Sum().As("questions"), r.Tokens.Sum().As("tokens")).
+		Where(r.UserID.Eq(userID), r.ID.Neq(excludeID), r.JobID.In(jobIDs...)).
+		Scan(&jobs); err != nil {
+		return total, fmt.Errorf("统计预占配额失败：%w", err)
+	}
+	total.Questions += jobs.Questions
+	total.Tokens += jobs.Tokens
+	return total, nil
+}
+
+// QuotaReservation 生成请求预占的配额（quota_reservations 表中的一条记录）
+type QuotaReservation struct {
+	id   int64
+	once sync.Once
+}
+
+// Release 结算预占：生成结束（用量已计入 ai_calls）后调用，重复调用只结算一次。
+// 请求已结束时仍需删除预占，因此不使用请求的上下文
+func (r *QuotaReservation) Release() {
+	if r == nil {
+		return
+	}
+	r.once.Do(func() {
+		deleteQuotaReservation(dao.QuotaReservation.ID.Eq(r.id))
+	})
+}
+
+// attachJob 将预占转交给异步任务：任务排队或执行中时预占有效，任务结束时由 releaseJobReservation 结算
+func (r *QuotaReservation) attachJob(ctx context.Context, jobID int64) error {
+	_, err := dao.Q.QuotaReservation.WithContext(ctx).
+		Where(dao.QuotaReservation.ID.Eq(r.id)).
+		UpdateSimple(dao.QuotaReservation.JobID.Value(jobID), dao.QuotaReservation.ExpiresAt.Null())
+	if err != nil {
+		return fmt.Errorf("关联任务预占失败：%w", err)
+	}
+	return nil
+}
+
+// releaseJobReservation 结算异步任务预占的配额
+func releaseJobReservation(jobID int64) {
+	deleteQuotaReservation(dao.QuotaReservation.JobID.Eq(jobID))
+}
+
+// deleteQuotaReservation 删除预占记录（失败时只记录日志：过期或任务结束后的预占不再计入）
+func deleteQuotaReservation(conds ...gen.Condition) {
+	if _, err := dao.Q.QuotaReservation.WithContext(context.Background()).Where(conds...).Delete(); err != nil {
+		log.Printf("警告：删除配额预占失败: %v", err)
+	}
+}
+
+// ReserveQuota 生成前检查配额并预占本次请求的题目数量和预估的 token 数（每道题目按 QUOTA_TOKENS_PER_QUESTION 估算）：
+// 各项配额需在已用和其他请求已预占的数量之外容纳本次请求的数量。超出时返回 utils.ErrQuotaExceeded 和配额状态
+// （含剩余量和重置时间）；通过时返回预占，调用方在生成结束后调用 Release 结算。
+// 先写入预占再检查，并发的请求（包括其他实例上的请求）相互可见，不会一起通过检查而超出配额
+func ReserveQuota(ctx context.Context, userID int64, count int, cfg *config.Config) (*QuotaReservation, *QuotaStatus, error) {
+	// 1. 写入预占（同步请求的预占在任务最长执行时间后过期，避免进程异常退出后一直占用配额）
+	tokens := int64(count) * cfg.QuotaTokensPerQuestion
+	expiresAt := time.Now().Add(time.Duration(cfg.JobTimeoutSeconds) * time.Second)
+	record := &models.QuotaReservation{UserID: userID, Questions: int64(count), Tokens: tokens, ExpiresAt: &expiresAt}
+	if err := dao.Q.QuotaReservation.WithContext(ctx).Create(record); err != nil {
+		return nil, nil, fmt.Errorf("预占配额失败：%w", err)
+	}
+	reservation := &QuotaReservation{id: record.ID}
+
+	// 2. 查询配额状态（扣除其他请求预占的数量）
+	status, err := getQuotaStatus(ctx, userID, cfg, record.ID)
+	if err != nil {
+		reservation.Release()
+		return nil, nil, err
+	}
+	status.Requested = count
+
+	// 3. 检查各项配额
+	var reasons []string
+	for _, quota := range status.Quotas {
+		if quota.Limit <= 0 {
+			continue
+		}
+		switch quota.Name {
+		case QuotaDailyQuestions, QuotaMonthlyQuestions:
+			if quota.Used+quota.Reserved+int64(count) > quota.Limit {
+				status.Exceeded = append(status.Exceeded, quota.Name)
+				reasons = append(reasons, fmt.Sprintf("%s剩余 %d 道题目，本次请求 %d 道，%s 重置",
+					quotaPeriodName(quota.Name), quota.Remaining, count, quota.ResetAt.Format(time.DateTime)))
+			}
+		default:
+			if quota.Used+quota.Reserved+tokens > quota.Limit {
+				status.Exceeded = append(status.Exceeded, quota.Name)
+				reasons = append(reasons, fmt.Sprintf("%s剩余 %d token，本次请求预计消耗 %d，%s 重置",
+					quotaPeriodName(quota.Name), quota.Remaining, tokens, quota.ResetAt.Format(time.DateTime)))
+			}
+		}
+	}
+	if len(reasons) > 0 {
+		reservation.Release()
+		return nil, status, fmt.Errorf("%w（%s）", utils.ErrQuotaExceeded, strings.Join(reasons, "；"))
+	}
+	return reservation, status, nil
+}
+
+// purgeQuotaReservations 删除已过期的同步请求预占和已结束的异步任务的预占（进程异常退出时未结算的记录）
+func purgeQuotaReservations(ctx context.Context, now time.Time) error {
+	r := dao.QuotaReservation
+	if _, err := dao.Q.QuotaReservation.WithContext(ctx).
+		Where(r.JobID.IsNull(), r.ExpiresAt.Lte(now)).
+		Delete(); err != nil {
+		return fmt.Errorf("删除过期的配额预占失败：%w", err)
+	}
+
+	j := dao.GenerationJob
+	pending := dao.Q.GenerationJob.WithContext(ctx).
+		Select(j.ID).
+		Where(j.Status.In(models.JobStatusQueued, models.JobStatusRunning))
+	if _, err := dao.Q.QuotaReservation.WithContext(ctx).
+		Where(r.JobID.IsNotNull(), r.Columns(r.JobID).NotIn(pending)).
+		Delete(); err != nil {
+		return fmt.Errorf("删除已结束任务的配额预占失败：%w", err)
+	}
+	return nil
+}
+
+// quotaPeriodName 配额周期的中文名称
+func quotaPeriodName(name string) string {
+	if strings.HasPrefix(name, "daily") {
+		return "今日"
+	}
+	return "本月"
+}
+
+// QuotaOverview 配额设置总览（管理员）
+type QuotaOverview struct {
+	Roles     map[string]config.QuotaLimits `json:"roles"`     // 各角色的默认配额
+	Overrides []*models.UserQuota           `json:"overrides"` // 单独设置了配额的用户
+}
+
+// GetQuotaOverview 查询角色默认配额和所有单独设置的用户配额
+func GetQuotaOverview(ctx context.Context, cfg *config.Config) (*QuotaOverview, error) {
+	overrides, err := dao.Q.UserQuota.WithContext(ctx).Order(dao.UserQuota.UserID).Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询用户配额失败：%w", err)
+	}
+	return &QuotaOverview{
+		Roles: map[string]config.QuotaLimits{
+			"user":  cfg.UserQuota,
+			"admin": cfg.AdminQuota,
+		},
+		Overrides: overrides,
+	}, nil
+}
+
+// UpdateQuotaRequest 设置用户配额的请求参数（整体替换，未传的项使用角色默认配额，0 表示不限）
+type UpdateQuotaRequest struct {
+	DailyQuestions   *int64 `json:"daily_questions" binding:"omitempty,min=0"`
+	MonthlyQuestions *int64 `json:"monthly_questions" binding:"omitempty,min=0"`
+	DailyTokens      *int64 `json:"daily_tokens" binding:"omitempty,min=0"`
+	MonthlyTokens    *int64 `json:"monthly_tokens" binding:"omitempty,min=0"`
+}
+
+// SetUserQuota 设置用户的单独配额（已有设置时整体替换，各项均未传时返回 utils.ErrEmptyQuota）
+func SetUserQuota(ctx context.Context, userID int64, req UpdateQuotaRequest) (*models.UserQuota, error) {
+	// 1. 检查参数和用户是否存在
+	if req.DailyQuestions == nil && req.MonthlyQuestions == nil && req.DailyTokens == nil && req.MonthlyTokens == nil {
+		return nil, utils.ErrEmptyQuota
+	}
+	exists, err := dao.Q.User.WithContext(ctx).Where(dao.User.ID.Eq(userID)).Count()
+	if err != nil {
+		return nil, fmt.Errorf("查询用户失败：%w", err)
+	}
+	if exists == 0 {
+		return nil, utils.ErrUserNotFound
+	}
+
+	// 2. 新增或替换配额设置
+	quota := &models.UserQuota{
+		UserID:           userID,
+		DailyQuestions:   req.DailyQuestions,
+		MonthlyQuestions: req.MonthlyQuestions,
+		DailyTokens:      req.DailyTokens,
+		MonthlyTokens:    req.MonthlyTokens,
+	}
+	err = dao.Q.UserQuota.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"daily_questions", "monthly_questions", "daily_tokens", "monthly_tokens", "updated_at"}),
+		}).
+		Create(quota)
+	if err != nil {
+		return nil, fmt.Errorf("保存用户配额失败：%w", err)
+	}
+	return quota, nil
+}
+
+// DeleteUserQuota 删除用户的单独配额（恢复使用角色默认配额）
+func DeleteUserQuota(ctx context.Context, userID int64) error {
+	if _, err := dao.Q.UserQuota.WithContext(ctx).Where(dao.UserQuota.UserID.Eq(userID)).Delete(); err != nil {
+		return fmt.Errorf("删除用户配额失败：%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"CodeQuizAI/config"
+	"CodeQuizAI/dao"
+	"CodeQuizAI/models"
+	"CodeQuizAI/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// 按本地仓库出题：管理员登记服务器上允许目录（SOURCE_REPO_ROOTS）中的仓库，生成时重新扫描仓库。
+// Go 包用 go/parser 和 go/doc 提取包注释以及导出的函数、方法、类型、常量和变量（含文档注释）作为主题（跳过测试和生成的代码），
+// 其他语言的源码和文档按文本拆分；题目的出处为文件相对于仓库的路径和行号
+
+// CreateRepositorySourceRequest 登记本地仓库的请求参数
+type CreateRepositorySourceRequest struct {
+	Path string `json:"path" binding:"required"` // 仓库路径（须位于 SOURCE_REPO_ROOTS 中的某个目录下）
+	Name string `json:"name" binding:"max=255"`  // 资料名称（可选，默认使用仓库目录名）
+}
+
+// repositoryScan 仓库的扫描结果
+type repositoryScan struct {
+	chunks []sourceChunk // 按文件打包的出题依据
+	topics []string      // 主题列表（每行形如 "services/foo.go:12-30 func Foo"）
+}
+
+// repositorySkipDirs 扫描时跳过的目录（另外跳过以 . 或 _ 开头的目录）
+var repositorySkipDirs = map[string]bool{
+	"vendor":       true,
+	"node_modules": true,
+	"testdata":     true,
+}
+
+// repositoryTextExts 按文本拆分的源码和文档的扩展名（Go 源码按包解析）
+var repositoryTextExts = map[string]bool{
+	".md": true, ".txt": true, ".rst": true,
+	".py": true, ".js": true, ".ts": true, ".jsx": true, ".tsx": true,
+	".java": true, ".kt": true, ".scala": true, ".c": true, ".h": true, ".cc": true, ".cpp": true, ".hpp": true,
+	".cs": true, ".rb": true, ".php": true, ".rs": true, ".swift": true, ".sh": true, ".sql": true, ".proto": true,
+}
+
+// CreateRepositorySource 登记本地仓库：扫描一次以确认有可出题的内容，保存仓库路径和主题列表
+func CreateRepositorySource(ctx context.Context, userID int64, req CreateRepositorySourceRequest, cfg *config.Config) (*SourceSummary, error) {
+	// 1. 校验路径并扫描仓库
+	root, err := resolveRepositoryPath(req.Path, cfg.SourceRepoRoots)
+	if err != nil {
+		return nil, err
+	}
+	scan, err := scanRepository(0, root, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 保存仓库路径，内容为扫描出的主题列表
+	content := strings.Join(scan.topics, "\n")
+	checksum := sha256.Sum256([]byte(content))
+	doc := &models.SourceDocument{
+		UserID:   userID,
+		Kind:     models.SourceKindRepository,
+		Name:     strings.TrimSpace(req.Name),
+		RepoPath: root,
+		Content:  content,
+		Size:     len(content),
+		Lines:    len(scan.topics),
+		Checksum: hex.EncodeToString(checksum[:]),
+	}
+	if doc.Name == "" {
+		doc.Name = filepath.Base(root)
+	}
+	if err := dao.Q.SourceDocument.WithContext(ctx).Create(doc); err != nil {
+		return nil, fmt.Errorf("保存出题资料失败：%w", err)
+	}
+
+	summary := newSourceSummary(doc)
+	summary.Chunks = len(scan.chunks)
+	return &summary, nil
+}
+
+// resolveRepositoryPath 解析仓库的真实路径（展开符号链接），必须是 roots 中某个目录或其子目录
+func resolveRepositoryPath(path string, roots []string) (string, error) {
+	if len(roots) == 0 {
+		return "", utils.ErrRepositoryDisabled
+	}
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil || !filepath.IsAbs(real) {
+		return "", fmt.Errorf("%w：%s", utils.ErrRepositoryNotAllowed, path)
+	}
+	if info, err := os.Stat(real); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("%w：%s", utils.ErrRepositoryNotAllowed, path)
+	}
+	for _, root := range roots {
+		rootReal, err := filepath.EvalSymlinks(root)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(rootReal, real)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return real, nil
+		}
+	}
+	return "", fmt.Errorf("%w：%s", utils.ErrRepositoryNotAllowed, path)
+}
+
+// scanRepository 扫描仓库中的源码和文档（重新校验路径，配置修改后不再允许的仓库不能继续出题）
+func scanRepository(sourceID int64, repoPath string, cfg *config.Config) (*repositoryScan, error) {
+	// 1. 收集文件：Go 源码按目录分组（不含测试文件），其他源码和文档单独拆分
+	root, err := resolveRepositoryPath(repoPath, cfg.SourceRepoRoots)
+	if err != nil {
+		return nil, err
+	}
+	goFiles := make(map[string][]string) // 目录 -> Go 源码文件
+	var goDirs, textFiles []string
+	files := 0
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || repositorySkipDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil // 不跟随符号链接
+		}
+		ext := filepath.Ext(name)
+		isGo := ext == ".go" && !strings.HasSuffix(name, "_test.go")
+		if !isGo && !repositoryTextExts[strings.ToLower(ext)] {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > int64(cfg.SourceMaxBytes) {
+			return nil // 超过单份资料大小上限的文件跳过
+		}
+		if files++; files > cfg.SourceRepoMaxFiles {
+			return fmt.Errorf("%w（超过 %d 个），请登记子目录", utils.ErrRepositoryTooLarge, cfg.SourceRepoMaxFiles)
+		}
+		if isGo {
+			dir := filepath.Dir(path)
+			if len(goFiles[dir]) == 0 {
+				goDirs = append(goDirs, dir)
+			}
+			goFiles[dir] = append(goFiles[dir], path)
+		} else {
+			textFiles = append(textFiles, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("扫描仓库失败：%w", err)
+	}
+
+	// 2. Go 包提取导出的 API 作为主题，解析失败的文件按文本拆分
+	scan := &repositoryScan{}
+	for _, dir := range goDirs {
+		segments, failed := goPackageTopics(root, goFiles[dir], cfg.SourceChunkBytes)
+		textFiles = append(textFiles, failed...)
+		for _, path := range sortedKeys(segments) {
+			scan.addChunks(packSegments(sourceID, path, segments[path], cfg.SourceChunkBytes))
+		}
+	}
+
+	// 3. 其他源码和文档按文本拆分
+	sort.Strings(textFiles)
+	for _, path := range textFiles {
+		data, err := os.ReadFile(path)
+		if err != nil || !utf8.Valid(data) {
+			continue
+		}
+		rel := repositoryRelPath(root, path)
+		scan.addChunks(splitText(sourceID, rel, strings.ReplaceAll(string(data), "\r\n", "\n"), cfg.SourceChunkBytes))
+	}
+	if len(scan.chunks) == 0 {
+		return nil, fmt.Errorf("%w（仓库中没有找到可出题的源码或文档）", utils.ErrSourceEmpty)
+	}
+	return scan, nil
+}
+
+// addChunks 加入资料段并记录其中的主题
+func (s *repositoryScan) addChunks(chunks []sourceChunk) {
+	for _, c := range chunks {
+		s.chunks = append(s.chunks, c)
+		for _, seg := range c.segments {
+			topic := fmt.Sprintf("%s:%d-%d", c.path, seg.startLine, seg.endLine)
+			if seg.title != "" {
+				topic += " " + seg.title
+			}
+			s.topics = append(s.topics, topic)
+		}
+	}
+}
+
+// goPackageTopics 解析同一目录下的 Go 源码，按包提取包注释和导出的声明（含文档注释），
+// 返回各文件（相对路径）的行区间，以及解析失败的文件
+func goPackageTopics(root string, paths []string, chunkBytes int) (map[string][]sourceSegment, []string) {
+	// 1. 解析源码，按包名分组（同一目录下可能有 package main 的工具文件等）
+	fset := token.NewFileSet()
+	packages := make(map[string][]*ast.File)
+	lines := make(map[string][]string) // 文件 -> 各行内容
+	var failed []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, data, parser.ParseComments)
+		if err != nil {
+			log.Printf("解析 Go 源码失败，按文本拆分: %v", err)
+			failed = append(failed, path)
+			continue
+		}
+		if ast.IsGenerated(file) {
+			continue // 跳过生成的代码（如 gorm/gen 生成的 DAO）
+		}
+		packages[file.Name.Name] = append(packages[file.Name.Name], file)
+		lines[path] = strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	}
+
+	// 2. 用 go/doc 提取导出的声明（按起始行记录，避免重复）
+	segments := make(map[string][]sourceSegment)
+	seen := make(map[string]bool)
+	add := func(title string, from, to token.Pos) {
+		start, end := fset.Position(from), fset.Position(to)
+		key := fmt.Sprintf("%s:%d", start.Filename, start.Line)
+		fileLines := lines[start.Filename]
+		if seen[key] || start.Line < 1 || end.Line > len(fileLines) {
+			return
+		}
+		seen[key] = true
+		seg := sourceSegment{startLine: start.Line, endLine: end.Line, title: title}
+		seg.text = strings.Join(fileLines[start.Line-1:end.Line], "\n")
+		for len(seg.text) > chunkBytes && seg.endLine > seg.startLine {
+			// 过长的声明（如大函数）只保留开头部分
+			seg.endLine--
+			seg.text = strings.Join(fileLines[seg.startLine-1:seg.endLine], "\n")
+		}
+		rel := repositoryRelPath(root, start.Filename)
+		segments[rel] = append(segments[rel], seg)
+	}
+	declStart := func(node ast.Node, docGroup *ast.CommentGroup) token.Pos {
+		if docGroup != nil {
+			return docGroup.Pos()
+		}
+		return node.Pos()
+	}
+	addValues := func(values []*doc.Value) {
+		for _, v := range values {
+			kind := "var"
+			if v.Decl.Tok == token.CONST {
+				kind = "const"
+			}
+			add(kind+" "+strings.Join(v.Names, ", "), declStart(v.Decl, v.Decl.Doc), v.Decl.End())
+		}
+	}
+	addFuncs := func(funcs []*doc.Func) {
+		for _, f := range funcs {
+			title := "func " + f.Name
+			if f.Recv != "" {
+				title = "func (" + f.Recv + ") " + f.Name
+			}
+			add(title, declStart(f.Decl, f.Decl.Doc), f.Decl.End())
+		}
+	}
+	for name, files := range packages {
+		pkg, err := doc.NewFromFiles(fset, files, name, doc.PreserveAST)
+		if err != nil {
+			log.Printf("提取 Go 包文档失败: %v", err)
+			continue
+		}
+		for _, file := range files {
+			if file.Doc != nil {
+				add("package "+pkg.Name, file.Doc.Pos(), file.Name.End())
+			}
+		}
+		addValues(pkg.Consts)
+		addValues(pkg.Vars)
+		addFuncs(pkg.Funcs)
+		for _, t := range pkg.Types {
+			add("type "+t.Name, declStart(t.Decl, t.Decl.Doc), t.Decl.End())
+			addValues(t.Consts)
+			addValues(t.Vars)
+			addFuncs(t.Funcs)
+			addFuncs(t.Methods)
+		}
+	}
+	return segments, failed
+}
+
+// packSegments 将同一文件的行区间按行号排序，打包为不超过 chunkBytes 的若干资料段（重叠的区间只保留前一个）
+func packSegments(sourceID int64, path string, segments []sourceSegment, chunkBytes int) []sourceChunk {
+	sort.Slice(segments, func(a, b int) bool { return segments[a].startLine < segments[b].startLine })
+
+	var chunks []sourceChunk
+	var current []sourceSegment
+	size, lastEnd := 0, 0
+	for _, seg := range segments {
+		if seg.startLine <= lastEnd {
+			continue
+		}
+		lastEnd = seg.endLine
+		if len(current) > 0 && size+len(seg.text) > chunkBytes {
+			chunks = append(chunks, sourceChunk{sourceID: sourceID, path: path, segments: current})
+			current, size = nil, 0
+		}
+		current = append(current, seg)
+		size += len(seg.text)
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, sourceChunk{sourceID: sourceID, path: path, segments: current})
+	}
+	return chunks
+}
+
+// repositoryRelPath 文件相对于仓库的路径（统一使用 / 分隔）
+func repositoryRelPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// sortedKeys 按字母序返回 map 的键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"CodeQuizAI/config"
+	"CodeQuizAI/dao"
+	"CodeQuizAI/models"
+	"CodeQuizAI/utils"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 按资料出题：用户上传源码文件、讲义等文本资料，生成时按大小拆分为多段，
+// 每段单独作为一次AI调用的出题依据，生成的题目记录出处（资料名称和行号范围）
+
+// CreateSourceRequest 上传出题资料的请求参数
+type CreateSourceRequest struct {
+	Name    string `json:"name" binding:"required,max=255"` // 资料名称（如文件名）
+	Content string `json:"content" binding:"required"`      // 资料内容（UTF-8 文本）
+}
+
+// SourceSummary 出题资料的概要（不含内容）
+type SourceSummary struct {
+	ID        int64     `json:"id"`                  // 资料ID
+	Kind      string    `json:"kind"`                // 资料类型（upload 上传的资料，repository 本地仓库）
+	Name      string    `json:"name"`                // 资料名称
+	RepoPath  string    `json:"repo_path,omitempty"` // 本地仓库的路径
+	Size      int       `json:"size"`                // 内容大小（字节）
+	Lines     int       `json:"lines"`               // 内容行数（本地仓库为登记时扫描出的主题数）
+	Chunks    int       `json:"chunks,omitempty"`    // 按当前配置拆分的段数（每段至少一次AI调用；本地仓库仅登记时返回）
+	Checksum  string    `json:"checksum"`            // 内容的 SHA-256
+	CreatedAt time.Time `json:"created_at"`          // 上传时间
+}
+
+// sourceChunk 资料拆分后的一段（一次AI调用的出题依据），由同一个文件中的一个或多个连续行区间组成
+type sourceChunk struct {
+	sourceID int64           // 资料ID
+	path     string          // 题目出处中的路径（上传的资料为资料名称，本地仓库为文件的相对路径）
+	segments []sourceSegment // 行区间（按行号排序）
+}
+
+// sourceSegment 资料中的一个连续行区间
+type sourceSegment struct {
+	startLine int    // 起始行号（从1开始）
+	endLine   int    // 结束行号（含）
+	title     string // 标题（如本地仓库中的 "func Foo"，上传的资料为空）
+	text      string // 内容
+}
+
+// CreateSourceDocument 保存用户上传的出题资料：内容必须是 UTF-8 文本且不超过大小上限
+func CreateSourceDocument(ctx context.Context, userID int64, req CreateSourceRequest, cfg *config.Config) (*SourceSummary, error) {
+	// 1. 校验大小和编码（换行统一为 \n，去掉 UTF-8 BOM）
+	if len(req.Content) > cfg.SourceMaxBytes {
+		return nil, fmt.Errorf("%w（%d 字节，上限 %d 字节）", utils.ErrSourceTooLarge, len(req.Content), cfg.SourceMaxBytes)
+	}
+	if !utf8.ValidString(req.Content) || strings.ContainsRune(req.Content, 0) {
+		return nil, utils.ErrSourceNotText
+	}
+	content := strings.TrimPrefix(strings.ReplaceAll(req.Content, "\r\n", "\n"), "\ufeff")
+	if strings.TrimSpace(content) == "" {
+		return nil, utils.ErrSourceEmpty
+	}
+
+	// 2. 保存资料
+	checksum := sha256.Sum256([]byte(content))
+	doc := &models.SourceDocument{
+		UserID:   userID,
+		Kind:     models.SourceKindUpload,
+		Name:     strings.TrimSpace(req.Name),
+		Content:  content,
+		Size:     len(content),
+		Lines:    strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1,
+		Checksum: hex.EncodeToString(checksum[:]),
+	}
+	if err := dao.Q.SourceDocument.WithContext(ctx).Create(doc); err != nil {
+		return nil, fmt.Errorf("保存出题资料失败：%w", err)
+	}
+
+	summary := newSourceSummary(doc)
+	summary.Chunks = len(splitSource(doc, cfg.SourceChunkBytes))
+	return &summary, nil
+}
+
+// ListSourceDocuments 查询用户上传的出题资料（按上传时间从新到旧，不含内容）
+func ListSourceDocuments(ctx context.Context, userID int64, cfg *config.Config) ([]SourceSummary, error) {
+	s := dao.SourceDocument
+	docs, err := dao.Q.SourceDocument.WithContext(ctx).
+		Where(s.UserID.Eq(userID)).
+		Order(s.ID.Desc()).
+		Find()
+	if err != nil {
+		return nil, fmt.Errorf("查询出题资料失败：%w", err)
+	}
+
+	summaries := make([]SourceSummary, len(docs))
+	for i, doc := range docs {
+		summaries[i] = newSourceSummary(doc)
+		if doc.Kind != models.SourceKindRepository { // 本地仓库不在列表中重新扫描
+			summaries[i].Chunks = len(splitSource(doc, cfg.SourceChunkBytes))
+		}
+	}
+	return summaries, nil
+}
+
+// GetSourceDocument 查询用户的出题资料（含内容）
+func GetSourceDocument(ctx context.Context, sourceID, userID int64) (*models.SourceDocument, error) {
+	doc, err := dao.Q.SourceDocument.WithContext(ctx).
+		Where(dao.SourceDocument.ID.Eq(sourceID), dao.SourceDocument.UserID.Eq(userID)).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrSourceNotFound
+		}
+		return nil, fmt.Errorf("查询出题资料失败：%w", err)
+	}
+	return doc, nil
+}
+
+// DeleteSourceDocument 删除用户的出题资料（软删除，已生成的题目保留出处，但不能再按资料重新生成）
+func DeleteSourceDocument(ctx context.Context, sourceID, userID int64) error {
+	info, err := dao.Q.SourceDocument.WithContext(ctx).
+		Where(dao.SourceDocument.ID.Eq(sourceID), dao.SourceDocument.UserID.Eq(userID)).
+		Delete()
+	if err != nil {
+		return fmt.Errorf("删除出题资料失败：%w", err)
+	}
+	if info.RowsAffected == 0 {
+		return utils.ErrSourceNotFound
+	}
+	return nil
+}
+
+// newSourceSummary 取出资料的概要
+func newSourceSummary(doc *models.SourceDocument) SourceSummary {
+	return SourceSummary{
+		ID:        doc.ID,
+		Kind:      doc.Kind,
+		Name:      doc.Name,
+		RepoPath:  doc.RepoPath,
+		Size:      doc.Size,
+		Lines:     doc.Lines,
+		Checksum:  doc.Checksum,
+		CreatedAt: doc.CreatedAt,
+	}
+}
+
+// splitSource 将上传的资料拆分为不超过 chunkBytes 的若干段
+func splitSource(doc *models.SourceDocument, chunkBytes int) []sourceChunk {
+	return splitText(doc.ID, doc.Name, doc.Content, chunkBytes)
+}
+
+// splitText 按行将文本拆分为不超过 chunkBytes 的若干段（单行超长时该行单独成段）。
+// 已超过一半大小时优先在段落或顶层声明的开头（空行后不缩进的行）处拆分，尽量保持代码和章节完整
+func splitText(sourceID int64, path, content string, chunkBytes int) []sourceChunk {
+	var chunks []sourceChunk
+	var b strings.Builder
+	start := 1
+	flush := func(end int) {
+		if strings.TrimSpace(b.String()) != "" {
+			chunks = append(chunks, sourceChunk{
+				sourceID: sourceID,
+				path:     path,
+				segments: []sourceSegment{{startLine: start, endLine: end, text: strings.TrimRight(b.String(), "\n")}},
+			})
+		}
+		b.Reset()
+		start = end + 1
+	}
+
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	prevBlank := false
+	for i, line := range lines {
+		lineNo := i + 1
+		boundary := prevBlank && line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t")
+		if b.Len() > 0 && (b.Len()+len(line)+1 > chunkBytes || (boundary && b.Len() >= chunkBytes/2)) {
+			flush(lineNo - 1)
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+		prevBlank = strings.TrimSpace(line) == ""
+	}
+	flush(len(lines))
+	return chunks
+}
+
+// sourceChunkAt 返回指定文件中包含指定行的资料段（资料内容已变化、找不到时返回 nil）
+func sourceChunkAt(chunks []sourceChunk, path string, line int) *sourceChunk {
+	for i := range chunks {
+		if chunks[i].path != path {
+			continue
+		}
+		for _, seg := range chunks[i].segments {
+			if line >= seg.startLine && line <= seg.endLine {
+				return &chunks[i]
+			}
+		}
+	}
+	return nil
+}
+
+// planSourceRequests 将题目数量分配到资料的各段：数量不少于段数时平均分配（超过每批上限的再分批），
+// 少于段数时均匀挑选若干段各出1道题
+func planSourceRequests(req GenerateQuestionRequest, chunks []sourceChunk, chunkSize int) []GenerateQuestionRequest {
+	counts := make([]int, len(chunks))
+	if req.Count < len(chunks) {
+		for i := 0; i < req.Count; i++ {
+			counts[i*len(chunks)/req.Count] = 1
+		}
+	} else {
+		for i := range counts {
+			counts[i] = req.Count / len(chunks)
+			if i < req.Count%len(chunks) {
+				counts[i]++
+			}
+		}
+	}
+
+	var reqs []GenerateQuestionRequest
+	for i, count := range counts {
+		for _, size := range chunkSizes(count, chunkSize) {
+			chunkReq := req
+			chunkReq.Count = size
+			chunkReq.source = &chunks[i]
+			reqs = append(reqs, chunkReq)
+		}
+	}
+	if len(reqs) > 1 {
+		for i := range reqs {
+			reqs[i].part, reqs[i].parts = i+1, len(reqs)
+		}
+	}
+	return reqs
+}
+
+// loadSourceChunks 查询资料并按配置拆分（本地仓库每次重新扫描，使用仓库的最新内容）
+func loadSourceChunks(ctx context.Context, sourceID, userID int64, cfg *config.Config) ([]sourceChunk, error) {
+	doc, err := GetSourceDocument(ctx, sourceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	var chunks []sourceChunk
+	if doc.Kind == models.SourceKindRepository {
+		scan, err := scanRepository(doc.ID, doc.RepoPath, cfg)
+		if err != nil {
+			return nil, err
+		}
+		chunks = scan.chunks
+	} else {
+		chunks = splitSource(doc, cfg.SourceChunkBytes)
+	}
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("出题资料 %s 没有可用的内容", doc.Name)
+	}
+	return chunks, nil
+}
+
+// numbered 带行号的资料内容（每行形如 "12| ..."，多个行区间之间注明区间的标题和行号范围）
+func (c *sourceChunk) numbered() string {
+	var b strings.Builder
+	for i, seg := range c.segments {
+		if len(c.segments) > 1 || seg.title != "" {
+			if i > 0 {
+				b.WriteByte('\n')
+			}
+			fmt.Fprintf(&b, "【%s 第%d-%d行】\n", cmp.Or(seg.title, c.path), seg.startLine, seg.endLine)
+		}
+		for j, line := range strings.Split(seg.text, "\n") {
+			fmt.Fprintf(&b, "%d| %s\n", seg.startLine+j, line)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// span 资料段覆盖的行号范围（第一个区间的起始行到最后一个区间的结束行）
+func (c *sourceChunk) span() (start, end int) {
+	return c.segments[0].startLine, c.segments[len(c.segments)-1].endLine
+}
+
+// prompt 按资料出题的提示语（内容带行号，要求模型给出每道题目依据的行号范围）
+func (c *sourceChunk) prompt() string {
+	var b strings.Builder
+	start, end := c.span()
+	fmt.Fprintf(&b, "请只根据下面的资料出题（资料：%s，第%d-%d行，每行开头为行号）：\n", c.path, start, end)
+	b.WriteString(c.numbered())
+	b.WriteString("\n\n要求：\n- 题目考查资料中的内容，不要超出资料范围\n")
+	b.WriteString("- 需要代码时，代码片段应逐字摘自资料原文（不含行号），可省略无关的行\n")
+	b.WriteString("- 每道题目增加 source_lines 字段，为题目依据的行号范围（如 \"12-18\"）")
+	return b.String()
+}
+
+// applyTo 记录临时题目的出处：模型给出的行号范围在某个行区间之内时使用该范围，否则使用整段的范围
+func (c *sourceChunk) applyTo(temp *models.TempQuestion, sourceLines json.RawMessage) {
+	sourceID := c.sourceID
+	temp.SourceID = &sourceID
+	temp.SourcePath = c.path
+	temp.SourceStartLine, temp.SourceEndLine = c.span()
+	start, end, ok := parseSourceLines(sourceLines)
+	if !ok {
+		return
+	}
+	for _, seg := range c.segments {
+		if start >= seg.startLine && end <= seg.endLine {
+			temp.SourceStartLine, temp.SourceEndLine = start, end
+			return
+		}
+	}
+}
+
+// parseSourceLines 解析模型给出的行号范围（如 "12-18"、"12" 或 12）
+func parseSourceLines(raw json.RawMessage) (start, end int, ok bool) {
+	if len(raw) == 0 {
+		return 0, 0, false
+	}
+	text := string(raw)
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		text = s
+	}
+	from, to, found := strings.Cut(strings.TrimSpace(text), "-")
+	start, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil {
+		return 0, 0, false
+	}
+	end = start
+	if found {
+		if end, err = strconv.Atoi(strings.TrimSpace(to)); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, end, start >= 1 && start <= end
+}
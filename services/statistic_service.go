@@ -11,6 +11,8 @@ type UserStatisticsResponse struct {
 	TotalQuestions int            `json:"total_questions"` // 总出题次数
 	TotalPapers    int            `json:"total_papers"`    // 总试卷数量
 	QuestionTypes  map[string]int `json:"question_types"`  // 题目类型分布
+	Difficulties   map[string]int `json:"difficulties"`    // 题目难度分布（未指定难度的计入 unspecified）
+	AIUsage        UserUsage      `json:"ai_usage"`        // AI调用用量和估算费用
 }
 
 // ActiveDetail 活跃详情
@@ -49,9 +51,56 @@ func GetUserStatistics(ctx context.Context, userID int64) (UserStatisticsRespons
 	}
 	result.QuestionTypes = questionTypes
 
+	// 4. 统计题目难度分布
+	difficulties, err := getDifficultyDistribution(ctx, &userID)
+	if err != nil {
+		return result, err
+	}
+	result.Difficulties = make(map[string]int, len(difficulties))
+	for difficulty, count := range difficulties {
+		result.Difficulties[difficulty] = int(count)
+	}
+
+	// 5. 统计AI调用用量
+	result.AIUsage, err = getUserUsage(ctx, userID)
+	if err != nil {
+		return result, err
+	}
+
 	return result, nil
 }
 
+// 获取题目难度分布（userID 为空时统计全部用户）
+func getDifficultyDistribution(ctx context.Context, userID *int64) (map[string]int64, error) {
+	type difficultyCount struct {
+		Difficulty string
+		Count      int64
+	}
+	var results []difficultyCount
+
+	query := dao.Q.Question.WithContext(ctx)
+	if userID != nil {
+		query = query.Where(dao.Question.UserID.Eq(*userID))
+	}
+	err := query.
+		Select(dao.Question.Difficulty, dao.Question.Difficulty.Count().As("count")).
+		Group(dao.Question.Difficulty).
+		Scan(&results)
+	if err != nil {
+		return nil, err
+	}
+
+	distribution := make(map[string]int64)
+	for _, item := range results {
+		key := item.Difficulty
+		if key == "" {
+			key = "unspecified"
+		}
+		distribution[key] += item.Count
+	}
+	return distribution, nil
+}
+
 // 获取题目类型分布
 func getQuestionTypeDistribution(ctx context.Context, userID int64) (map[string]int, error) {
 	type typeCount struct {
@@ -84,7 +133,9 @@ type StatisticsOverview struct {
 	TotalPapers               int64            `json:"total_papers"`
 	LanguageDistribution      map[string]int64 `json:"language_distribution"`
 	AIModelUsage              map[string]int64 `json:"ai_model_usage"`
+	DifficultyDistribution    map[string]int64 `json:"difficulty_distribution"`
 	PaperQuestionDistribution map[string]int64 `json:"paper_question_distribution"`
+	PreviewJanitor            *JanitorStatus   `json:"preview_janitor"` // 过期预览清理任务的运行情况
 }
 
 // GetStatisticsOverview 获取系统整体统计信息
@@ -122,12 +173,24 @@ func GetStatisticsOverview(ctx context.Context) (StatisticsOverview, error) {
 		return overview, err
 	}
 
-	// 6. 试卷题目数量分布（使用字段表达式）
+	// 6. 题目难度分布
+	overview.DifficultyDistribution, err = getDifficultyDistribution(ctx, nil)
+	if err != nil {
+		return overview, err
+	}
+
+	// 7. 试卷题目数量分布（使用字段表达式）
 	overview.PaperQuestionDistribution, err = getPaperQuestionDistribution(ctx)
 	if err != nil {
 		return overview, err
 	}
 
+	// 8. 过期预览清理任务的运行情况
+	overview.PreviewJanitor, err = getJanitorStatus(ctx)
+	if err != nil {
+		return overview, err
+	}
+
 	return overview, nil
 }
 
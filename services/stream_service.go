@@ -0,0 +1,225 @@
+package services
+
+import (
+	"CodeQuizAI/ai"
+	"CodeQuizAI/config"
+	"CodeQuizAI/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// GenerateQuestionsStream 流式生成题目：每从模型输出中解析出一道题目，就立即保存为临时题目并回调 emit。
+// 所有题目使用同一个 preview_id，确认入库流程与非流式生成一致。
+// 降级仅在当前模型尚未输出任何题目时进行，避免同一批次混入不同模型的题目。
+// 流式生成不分批，题目数量不超过每批上限（由调用方校验）。
+// 开启答案校验时，所有题目推送完成后再统一校验，并更新已保存的题目
+func GenerateQuestionsStream(
+	ctx context.Context,
+	previewID string,
+	userID int64,
+	req GenerateQuestionRequest,
+	cfg *config.Config,
+	emit func(question models.TempQuestion),
+) (*GenerateQuestionsResult, error) {
+	result, err := streamQuestions(ctx, previewID, userID, req, cfg, emit)
+	if err != nil || !req.Verify {
+		return result, err
+	}
+
+	// 生成结束（已释放并发名额）后校验答案，校验结果保存失败只记录日志
+	result.Verification = verifyQuestions(ctx, previewID, userID, req, cfg, result.Questions)
+	if err := saveTempQuestionVerification(ctx, result.Questions); err != nil {
+		log.Printf("警告：%v，preview_id=%s", err, previewID)
+	}
+	return result, nil
+}
+
+// streamQuestions 占用并发名额后按模型顺序流式生成，直到某个模型输出了题目
+func streamQuestions(
+	ctx context.Context,
+	previewID string,
+	userID int64,
+	req GenerateQuestionRequest,
+	cfg *config.Config,
+	emit func(question models.TempQuestion),
+) (*GenerateQuestionsResult, error) {
+	// 1. 等待并发名额，确定模型尝试顺序
+	result := &GenerateQuestionsResult{}
+	release, err := generationLimiter(cfg).acquire(ctx, userID)
+	if err != nil {
+		return result, err
+	}
+	defer release()
+	modelChain := buildModelChain(req, cfg)
+
+	// 2. 按顺序尝试，直到某个模型输出了题目
+	var lastErr error
+	for _, model := range modelChain {
+		batch, err := streamWithModel(ctx, model, previewID, userID, req, cfg, emit)
+		result.Questions = batch.Questions
+		result.ParseErrors = batch.ParseErrors
+		result.Rejected = batch.Rejected
+		if err != nil {
+			lastErr = err
+			result.Attempts = append(result.Attempts, ModelAttempt{Model: model, Error: err.Error()})
+			// 已输出题目或请求已取消时不再尝试后续模型
+			if len(batch.Questions) > 0 || ctx.Err() != nil {
+				break
+			}
+			log.Printf("AI模型 %s 流式生成失败，尝试下一个模型: %v", model, err)
+			continue
+		}
+
+		result.Attempts = append(result.Attempts, ModelAttempt{Model: model, Cached: batch.Cached})
+		result.AIModel = model
+		result.FallbackUsed = model != req.AIModel
+		return result, nil
+	}
+
+	// 3. 生成中断时返回已保存的部分题目，便于调用方告知客户端
+	if len(result.Questions) > 0 {
+		return result, fmt.Errorf("生成中断（已保存 %d 道题目）：%w", len(result.Questions), lastErr)
+	}
+	if len(result.Attempts) > 1 {
+		return result, fmt.Errorf("所有模型均生成失败（已尝试 %d 个）：%w", len(result.Attempts), lastErr)
+	}
+	return result, lastErr
+}
+
+// streamWithModel 使用指定模型流式生成题目：逐题校验，合格的立即保存并推送，
+// 不合格的在流结束后统一要求模型修正。出错时也返回已保存的部分结果
+func streamWithModel(
+	ctx context.Context,
+	model string,
+	previewID string,
+	userID int64,
+	req GenerateQuestionRequest,
+	cfg *config.Config,
+	emit func(question models.TempQuestion),
+) (*generatedBatch, error) {
+	batch := &generatedBatch{}
+
+	// 1. 获取AI模型（已启用的模型均支持流式调用，不支持的会退化为一次性输出）
+	provider, err := ai.Get(model)
+	if err != nil {
+		return batch, err
+	}
+	streamer, ok := provider.(ai.StreamProvider)
+	if !ok {
+		return batch, fmt.Errorf("AI模型 %s 不支持流式输出", model)
+	}
+
+	// 2. 选择模板并构造AI提示语
+	prompt, version, err := renderPrompt(ctx, req, model)
+	if err != nil {
+		return batch, err
+	}
+
+	// 3. 加载题库签名，用于标记每道题目最相似的已有题目（加载失败不影响生成）
+	index, err := loadSimilarityIndex(ctx, userID, req.Language)
+	if err != nil {
+		log.Printf("近似重复检测失败: %v", err)
+		index = &similarityIndex{}
+	}
+
+	// 4. 保存并推送合格的题目（保存失败时取消流式调用）
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		saveErr error
+		objects jsonObjectStream
+		items   int                     // 已接收的题目对象数量
+		titles  = make(map[string]bool) // 已接受的题目标题（去重）
+	)
+	save := func(aq aiQuestion) {
+		if saveErr != nil {
+			return
+		}
+		questions := []models.TempQuestion{toTempQuestion(aq, req, model, previewID, userID, len(batch.Questions))}
+		questions[0].TemplateVersion = version
+		index.markSimilar(&questions[0])
+		if err := saveTempQuestions(streamCtx, questions); err != nil {
+			saveErr = fmt.Errorf("存储临时题目失败：%w", err)
+			cancel()
+			return
+		}
+		batch.Questions = append(batch.Questions, questions[0]) // 保存后带有数据库生成的ID和创建时间
+		emit(questions[0])
+	}
+	accept := func(raw string) {
+		index := items
+		items++
+		if saveErr != nil {
+			return
+		}
+		var aq aiQuestion
+		if err := json.Unmarshal([]byte(raw), &aq); err != nil {
+			batch.ParseErrors = append(batch.ParseErrors, ItemParseError{Index: index, Error: err.Error(), Raw: truncate(raw, maxRawLength)})
+			return
+		}
+		valid, rejected := validateBatch([]aiQuestion{aq}, req.QuestionType, titles)
+		batch.Rejected = append(batch.Rejected, rejected...)
+		for _, q := range valid {
+			save(q)
+		}
+	}
+
+	// 5. 调用流式接口，边接收边解析（优先使用缓存的输出，记录用量）
+	call := newAICall(cfg, userID, previewID, models.AICallPurposeStream)
+	cache := newResponseCache(cfg, req, model, version, prompt)
+	aiResp, err := call.chatStreamCached(withMockFailure(streamCtx, req), streamer, cache, &ai.Request{Prompt: prompt}, func(delta string) {
+		for _, raw := range objects.Write(delta) {
+			accept(raw)
+		}
+	})
+	call.countQuestions(ctx, len(batch.Questions))
+	if saveErr != nil {
+		return batch, saveErr
+	}
+	if err != nil {
+		return batch, fmt.Errorf("AI接口调用失败：%w", err)
+	}
+	if objects.Open() {
+		batch.ParseErrors = append(batch.ParseErrors, ItemParseError{Index: items, Error: "响应被截断或格式错误，该题目不完整"})
+	}
+
+	// 6. 增量解析未识别出题目时（如模型返回了单个对象），按完整内容解析一次
+	if items == 0 {
+		aiQuestions, parseErrs, err := extractAIQuestions(aiResp.Content)
+		if err != nil {
+			return batch, fmt.Errorf("解析AI结果失败：%w", err)
+		}
+		batch.ParseErrors = parseErrs
+		valid, rejected := validateBatch(aiQuestions, req.QuestionType, titles)
+		batch.Rejected = rejected
+		for _, aq := range valid {
+			save(aq)
+		}
+		call.countQuestions(ctx, len(batch.Questions))
+	}
+
+	// 7. 不合格的题目要求模型修正，修正后合格的继续保存并推送（一并计入本次流式调用）
+	if len(batch.Rejected) > 0 && cfg.AIMaxReprompts > 0 && saveErr == nil {
+		var fixed []aiQuestion
+		fixed, batch.Rejected = repromptRejected(streamCtx, provider, call, req, batch.Rejected, titles, cfg.AIMaxReprompts)
+		for _, aq := range fixed {
+			save(aq)
+		}
+		call.countQuestions(ctx, len(batch.Questions))
+	}
+	if saveErr != nil {
+		return batch, saveErr
+	}
+	if len(batch.Questions) == 0 {
+		return batch, errNoValidQuestions(batch.ParseErrors, batch.Rejected)
+	}
+	if !call.cacheHit() {
+		cache.put(ctx, aiResp.Content) // 生成出有效题目的输出才写入缓存
+	}
+	batch.Cached = call.cacheHit()
+
+	return batch, nil
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"CodeQuizAI/ai"
+	"CodeQuizAI/config"
+	"CodeQuizAI/dao"
+	"CodeQuizAI/models"
+	"context"
+	"fmt"
+	"gorm.io/gen/field"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// aiCall 一次AI调用的记录信息：调用结束后将用量、耗时、状态和估算费用写入 ai_calls 表
+type aiCall struct {
+	userID    int64
+	previewID string
+	purpose   string
+	prices    map[string]config.AIPrice
+	lastID    int64  // 最近一次调用的记录ID
+	cache     string // 最近一次调用的缓存使用情况（hit/miss，未使用缓存时为空）
+}
+
+// newAICall 创建AI调用的记录信息
+func newAICall(cfg *config.Config, userID int64, previewID, purpose string) *aiCall {
+	return &aiCall{userID: userID, previewID: previewID, purpose: purpose, prices: cfg.AIPrices}
+}
+
+// withPurpose 复制一份用于其他用途的记录信息（如修正题目）
+func (c *aiCall) withPurpose(purpose string) *aiCall {
+	return &aiCall{userID: c.userID, previewID: c.previewID, purpose: purpose, prices: c.prices}
+}
+
+// chat 调用模型并记录用量
+func (c *aiCall) chat(ctx context.Context, provider ai.Provider, req *ai.Request) (*ai.Response, error) {
+	start := time.Now()
+	resp, err := provider.Chat(ctx, req)
+	c.record(ctx, provider.Name(), resp, err, time.Since(start))
+	return resp, err
+}
+
+// chatStream 流式调用模型并记录用量
+func (c *aiCall) chatStream(ctx context.Context, streamer ai.StreamProvider, req *ai.Request, onDelta func(delta string)) (*ai.Response, error) {
+	start := time.Now()
+	resp, err := streamer.ChatStream(ctx, req, onDelta)
+	c.record(ctx, streamer.Name(), resp, err, time.Since(start))
+	return resp, err
+}
+
+// record 保存调用记录（请求取消后仍然保存，保存失败只记录日志，不影响调用结果）
+func (c *aiCall) record(ctx context.Context, model string, resp *ai.Response, callErr error, latency time.Duration) {
+	call := &models.AICall{
+		UserID:    c.userID,
+		AiModel:   model,
+		Purpose:   c.purpose,
+		PreviewID: c.previewID,
+		LatencyMs: latency.Milliseconds(),
+		Status:    models.AICallStatusSuccess,
+		Cache:     c.cache,
+	}
+	if resp != nil {
+		call.PromptTokens = resp.Usage.PromptTokens
+		call.CompletionTokens = resp.Usage.CompletionTokens
+		call.Cost = c.prices[model].Cost(call.PromptTokens, call.CompletionTokens)
+	}
+	if callErr != nil {
+		call.Status = models.AICallStatusError
+		call.Error = truncate(callErr.Error(), 500)
+	}
+
+	c.lastID = 0
+	if err := dao.Q.AICall.WithContext(context.WithoutCancel(ctx)).Create(call); err != nil {
+		log.Printf("警告：保存AI调用记录失败，model=%s, err=%v", model, err)
+		return
+	}
+	c.lastID = call.ID
+}
+
+// countQuestions 记录最近一次调用产出的合格题目数量（计入题目配额）
+func (c *aiCall) countQuestions(ctx context.Context, n int) {
+	if c.lastID == 0 {
+		return
+	}
+	if _, err := dao.Q.AICall.WithContext(context.WithoutCancel(ctx)).
+		Where(dao.AICall.ID.Eq(c.lastID)).
+		Update(dao.AICall.Questions, n); err != nil {
+		log.Printf("警告：更新AI调用记录失败，id=%d, err=%v", c.lastID, err)
+	}
+}
+
+// 用量汇总的分组方式
+const (
+	UsageGroupByUser  = "user"
+	UsageGroupByModel = "model"
+	UsageGroupByDay   = "day"
+)
+
+// UsageQuery 用量汇总的查询参数
+type UsageQuery struct {
+	GroupBy string `form:"group_by" binding:"omitempty,oneof=user model day"` // 分组方式（默认 day）
+	From    string `form:"from" binding:"omitempty,datetime=2006-01-02"`      // 起始日期（含）
+	To      string `form:"to" binding:"omitempty,datetime=2006-01-02"`        // 截止日期（含）
+	UserID  int64  `form:"user_id"`                                           // 只统计该用户（可选）
+	Model   string `form:"model"`                                             // 只统计该模型（可选）
+}
+
+// UsageSummary 一组调用的用量汇总
+type UsageSummary struct {
+	Calls            int64   `json:"calls"`             // 调用次数
+	FailedCalls      int64   `json:"failed_calls"`      // 失败次数
+	PromptTokens     int64   `json:"prompt_tokens"`     // 提示语 token 数
+	CompletionTokens int64   `json:"completion_tokens"` // 生成内容 token 数
+	Cost             float64 `json:"cost"`              // 估算费用
+	AvgLatencyMs     int64   `json:"avg_latency_ms"`    // 平均耗时（毫秒）
+	CacheHits        int64   `json:"cache_hits"`        // 命中响应缓存的次数（计入调用次数，token 和费用为0）
+	CacheMisses      int64   `json:"cache_misses"`      // 未命中响应缓存、实际调用模型的次数
+
+	latencyMs int64 // 总耗时（用于计算平均耗时）
+}
+
+// UsageRow 按分组汇总的用量
+type UsageRow struct {
+	Key      string `json:"key"`                // 分组值（用户名、模型名或日期）
+	UserID   int64  `json:"user_id,omitempty"`  // 用户ID（按用户分组时）
+	Username string `json:"username,omitempty"` // 用户名（按用户分组时）
+	UsageSummary
+}
+
+// UsageReport 用量汇总结果
+type UsageReport struct {
+	GroupBy string       `json:"group_by"` // 分组方式
+	Total   UsageSummary `json:"total"`    // 合计
+	Rows    []UsageRow   `json:"rows"`     // 各分组的汇总（按日期分组时按日期排序，其余按费用从高到低）
+}
+
+// usageAggregate 按分组、状态和缓存使用情况聚合的查询结果
+type usageAggregate struct {
+	Key              string
+	Status           string
+	Cache            string
+	Calls            int64
+	PromptTokens     int64
+	CompletionTokens int64
+	Cost             float64
+	LatencyMs        int64
+}
+
+// add 累加一组聚合结果
+func (s *UsageSummary) add(a usageAggregate) {
+	s.Calls += a.Calls
+	if a.Status == models.AICallStatusError {
+		s.FailedCalls += a.Calls
+	}
+	switch a.Cache {
+	case models.AICallCacheHit:
+		s.CacheHits += a.Calls
+	case models.AICallCacheMiss:
+		s.CacheMisses += a.Calls
+	}
+	s.PromptTokens += a.PromptTokens
+	s.CompletionTokens += a.CompletionTokens
+	s.Cost += a.Cost
+	s.latencyMs += a.LatencyMs
+}
+
+// finish 计算平均耗时并将费用保留6位小数
+func (s *UsageSummary) finish() {
+	if s.Calls > 0 {
+		s.AvgLatencyMs = s.latencyMs / s.Calls
+	}
+	s.Cost = math.Round(s.Cost*1e6) / 1e6
+}
+
+// GetUsageReport 按用户、模型或日期汇总AI调用的用量和费用
+func GetUsageReport(ctx context.Context, query UsageQuery) (*UsageReport, error) {
+	if query.GroupBy == "" {
+		query.GroupBy = UsageGroupByDay
+	}
+	c := dao.AICall
+
+	// 1. 筛选条件
+	q := dao.Q.AICall.WithContext(ctx)
+	if query.From != "" {
+		from, _ := time.ParseInLocation(time.DateOnly, query.From, time.Local)
+		q = q.Where(c.CreatedAt.Gte(from))
+	}
+	if query.To != "" {
+		to, _ := time.ParseInLocation(time.DateOnly, query.To, time.Local)
+		q = q.Where(c.CreatedAt.Lt(to.AddDate(0, 0, 1)))
+	}
+	if query.UserID > 0 {
+		q = q.Where(c.UserID.Eq(query.UserID))
+	}
+	if query.Model != "" {
+		q = q.Where(c.AiModel.Eq(query.Model))
+	}
+
+	// 2. 按分组、状态和缓存使用情况聚合
+	var key field.Expr
+	switch query.GroupBy {
+	case UsageGroupByUser:
+		key = c.UserID
+	case UsageGroupByModel:
+		key = c.AiModel
+	default:
+		key = c.CreatedAt.Date()
+	}
+	var aggregates []usageAggregate
+	err := q.Select(
+		key.As("key"),
+		c.Status,
+		c.Cache,
+		c.ID.Count().As("calls"),
+		c.PromptTokens.Sum().As("prompt_tokens"),
+		c.CompletionTokens.Sum().As("completion_tokens"),
+		c.Cost.Sum().As("cost"),
+		c.LatencyMs.Sum().As("latency_ms"),
+	).Group(key, c.Status, c.Cache).Scan(&aggregates)
+	if err != nil {
+		return nil, fmt.Errorf("汇总AI调用记录失败：%w", err)
+	}
+
+	// 3. 合并同一分组不同状态的结果
+	report := &UsageReport{GroupBy: query.GroupBy, Rows: []UsageRow{}}
+	rows := make(map[string]*UsageRow)
+	var keys []string
+	for _, a := range aggregates {
+		row, ok := rows[a.Key]
+		if !ok {
+			row = &UsageRow{Key: a.Key}
+			rows[a.Key] = row
+			keys = append(keys, a.Key)
+		}
+		row.add(a)
+		report.Total.add(a)
+	}
+	report.Total.finish()
+
+	// 4. 按用户分组时补充用户名
+	if query.GroupBy == UsageGroupByUser && len(keys) > 0 {
+		ids := make([]int64, 0, len(keys))
+		for _, key := range keys {
+			id, _ := strconv.ParseInt(key, 10, 64)
+			rows[key].UserID = id
+			ids = append(ids, id)
+		}
+		users, err := dao.Q.User.WithContext(ctx).Where(dao.User.ID.In(ids...)).Find()
+		if err != nil {
+			return nil, fmt.Errorf("查询用户失败：%w", err)
+		}
+		for _, user := range users {
+			key := strconv.FormatInt(user.ID, 10)
+			rows[key].Username = user.Username
+			rows[key].Key = user.Username
+		}
+	}
+
+	for _, key := range keys {
+		rows[key].finish()
+		report.Rows = append(report.Rows, *rows[key])
+	}
+	sort.Slice(report.Rows, func(a, b int) bool {
+		if query.GroupBy == UsageGroupByDay {
+			return report.Rows[a].Key < report.Rows[b].Key
+		}
+		return report.Rows[a].Cost > report.Rows[b].Cost
+	})
+	return report, nil
+}
+
+// UserUsage 用户自己的AI用量（用户统计中展示）
+type UserUsage struct {
+	UsageSummary
+	Models []UsageRow `json:"models"` // 按模型汇总
+}
+
+// getUserUsage 汇总用户的AI调用用量
+func getUserUsage(ctx context.Context, userID int64) (UserUsage, error) {
+	report, err := GetUsageReport(ctx, UsageQuery{GroupBy: UsageGroupByModel, UserID: userID})
+	if err != nil {
+		return UserUsage{}, err
+	}
+	return UserUsage{UsageSummary: report.Total, Models: report.Rows}, nil
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"CodeQuizAI/ai"
+	"CodeQuizAI/config"
+	"CodeQuizAI/dao"
+	"CodeQuizAI/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// 答案校验：生成后由校验模型在看不到答案和解析的情况下独立作答，
+// 与生成的答案不一致的题目标记为 flagged 并附上校验模型的理由，由人工在预览中复核
+
+// VerificationSummary 答案校验结果汇总
+type VerificationSummary struct {
+	Model      string   `json:"model"`            // 校验模型
+	Verified   int      `json:"verified"`         // 答案一致的题目数量
+	Flagged    int      `json:"flagged"`          // 答案不一致、需人工复核的题目数量
+	Unverified int      `json:"unverified"`       // 未能校验的题目数量（题型不支持、调用失败或答案无法解析）
+	Errors     []string `json:"errors,omitempty"` // 校验模型调用失败的原因
+}
+
+// reviewItem 校验模型待作答的题目（不含答案和解析）
+type reviewItem struct {
+	Title       string   `json:"title"`
+	Options     []string `json:"options,omitempty"`
+	CodeSnippet string   `json:"code_snippet,omitempty"`
+}
+
+// reviewAnswer 校验模型对单道题目的作答
+type reviewAnswer struct {
+	Index     int             `json:"index"`     // 题目序号（从1开始）
+	Answer    json.RawMessage `json:"answer"`    // 答案（格式同生成题目）
+	Reasoning string          `json:"reasoning"` // 作答理由
+}
+
+// maxReasoningLength 保存的作答理由最大长度（按字符）
+const maxReasoningLength = 1000
+
+// verifyModel 本次请求使用的校验模型（未指定时使用服务端配置）
+func verifyModel(req GenerateQuestionRequest, cfg *config.Config) string {
+	if req.VerifyModel != "" {
+		return req.VerifyModel
+	}
+	return cfg.AIVerifyModel
+}
+
+// CheckVerifyModel 开启答案校验时检查校验模型是否已指定且已启用
+func CheckVerifyModel(req GenerateQuestionRequest, cfg *config.Config) error {
+	if !req.Verify {
+		return nil
+	}
+	model := verifyModel(req, cfg)
+	if model == "" {
+		return errors.New("未指定校验模型（请求参数 verify_model 或配置 AI_VERIFY_MODEL）")
+	}
+	if _, err := ai.Get(model); err != nil {
+		return fmt.Errorf("校验模型不可用：%w", err)
+	}
+	return nil
+}
+
+// verifyQuestions 由校验模型分批独立作答并更新题目的校验状态（不落库）。
+// 校验失败不影响生成结果，相应题目保持 unverified
+func verifyQuestions(
+	ctx context.Context,
+	previewID string,
+	userID int64,
+	req GenerateQuestionRequest,
+	cfg *config.Config,
+	questions []models.TempQuestion,
+) *VerificationSummary {
+	model := verifyModel(req, cfg)
+	summary := &VerificationSummary{Model: model}
+
+	// 1. 挑出题型支持校验的题目，按每批上限拆分
+	var targets []int
+	for i := range questions {
+		questions[i].VerificationStatus = models.VerificationUnverified
+		if spec, ok := questionTypes[questions[i].QuestionType]; ok && spec.matchAnswer != nil {
+			targets = append(targets, i)
+		}
+	}
+	provider, err := ai.Get(model)
+	if err != nil {
+		summary.Errors = append(summary.Errors, err.Error())
+		targets = nil
+	}
+
+	// 2. 各批并发作答（并发数由全局和单用户上限控制）
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	start := 0
+	for _, size := range chunkSizes(len(targets), cfg.GenerateChunkSize) {
+		batch := targets[start : start+size]
+		start += size
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			call := newAICall(cfg, userID, previewID, models.AICallPurposeVerify)
+			answers, err := reviewBatch(ctx, provider, call, req, cfg, questions, batch)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("校验模型 %s 作答失败: %v", model, err)
+				summary.Errors = append(summary.Errors, err.Error())
+				return
+			}
+			for j, i := range batch {
+				applyReview(&questions[i], model, answers[j])
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 3. 汇总
+	for _, q := range questions {
+		switch q.VerificationStatus {
+		case models.VerificationVerified:
+			summary.Verified++
+		case models.VerificationFlagged:
+			summary.Flagged++
+		default:
+			summary.Unverified++
+		}
+	}
+	return summary
+}
+
+// reviewBatch 请求校验模型作答一批题目，按题目顺序返回作答结果（缺失的题目为空）
+func reviewBatch(
+	ctx context.Context,
+	provider ai.Provider,
+	call *aiCall,
+	req GenerateQuestionRequest,
+	cfg *config.Config,
+	questions []models.TempQuestion,
+	batch []int,
+) ([]*reviewAnswer, error) {
+	// 1. 等待并发名额
+	release, err := generationLimiter(cfg).acquire(ctx, call.userID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// 2. 构造作答请求（不含答案和解析）
+	items := make([]reviewItem, len(batch))
+	for j, i := range batch {
+		var options []string
+		_ = json.Unmarshal([]byte(questions[i].Options), &options)
+		items[j] = reviewItem{Title: questions[i].Title, Options: options, CodeSnippet: questions[i].CodeSnippet}
+	}
+	aiReq := &ai.Request{Prompt: buildReviewPrompt(req.Language, req.QuestionType, items)}
+	aiResp, err := call.chat(withMockFailure(ctx, req), provider, aiReq)
+	if err != nil {
+		return nil, fmt.Errorf("AI接口调用失败：%w", err)
+	}
+
+	// 3. 解析作答结果（优先按 index 对应题目，缺少 index 时按顺序对应）
+	elements, ok := decodeQuestionItems(aiResp.Content)
+	if !ok {
+		elements, ok = decodeQuestionItems(stripCodeFence(aiResp.Content))
+	}
+	if !ok {
+		return nil, fmt.Errorf("解析作答结果失败，响应内容：%s", truncate(aiResp.Content, maxRawLength))
+	}
+	answers := make([]*reviewAnswer, len(batch))
+	for position, element := range elements {
+		var answer reviewAnswer
+		if json.Unmarshal([]byte(element), &answer) != nil {
+			continue
+		}
+		index := position
+		if answer.Index >= 1 && answer.Index <= len(batch) {
+			index = answer.Index - 1
+		}
+		if index < len(batch) && answers[index] == nil {
+			answers[index] = &answer
+		}
+	}
+	return answers, nil
+}
+
+// buildReviewPrompt 构造校验模型独立作答的提示语
+func buildReviewPrompt(language, questionType string, items []reviewItem) string {
+	spec := questionTypes[questionType]
+	var b strings.Builder
+	fmt.Fprintf(&b, "请独立作答以下%d道关于%s语言的%s，逐题给出你认为正确的答案和简要理由。\n", len(items), language, spec.Name)
+	for i, item := range items {
+		itemJSON, _ := json.Marshal(item)
+		fmt.Fprintf(&b, "\n第%d题：%s\n", i+1, itemJSON)
+	}
+	fmt.Fprintf(&b, "\n要求：\n- index 为题目序号（从1开始）\n- %s\n- reasoning 为简要的作答理由\n\n", spec.ReviewRule)
+	b.WriteString("严格按题目顺序返回JSON数组，无额外内容：\n[\n  {\"index\":1,\"answer\":...,\"reasoning\":\"...\"}\n]")
+	return b.String()
+}
+
+// applyReview 比对校验模型的答案与生成的答案，更新题目的校验状态
+func applyReview(temp *models.TempQuestion, model string, answer *reviewAnswer) {
+	temp.ReviewerModel = model
+	if answer == nil {
+		temp.ReviewerReasoning = "校验模型未返回该题的答案"
+		return
+	}
+	temp.ReviewerReasoning = truncate(strings.TrimSpace(answer.Reasoning), maxReasoningLength)
+
+	// 答案按题型规范化后比较（校验模型的答案无法解析时保持未校验）
+	spec := questionTypes[temp.QuestionType]
+	var options []string
+	_ = json.Unmarshal([]byte(temp.Options), &options)
+	normalized, _ := spec.parseAnswer(answer.Answer, options)
+	if normalized == "" || normalized == "[]" {
+		temp.ReviewerReasoning = "校验模型的答案无法解析：" + truncate(string(answer.Answer), maxRawLength)
+		return
+	}
+	temp.ReviewerAnswer = normalized
+	temp.VerificationStatus = models.VerificationFlagged
+	if spec.matchAnswer(temp.Answer, normalized) {
+		temp.VerificationStatus = models.VerificationVerified
+	}
+}
+
+// reviewedContent 校验模型作答依据的题目内容和被比对的答案，任一项修改后原校验结果不再适用
+type reviewedContent struct {
+	questionType, title, codeSnippet, options, answer string
+}
+
+// tempReviewedContent 临时题目的校验依据
+func tempReviewedContent(q models.TempQuestion) reviewedContent {
+	return reviewedContent{q.QuestionType, q.Title, q.CodeSnippet, q.Options, q.Answer}
+}
+
+// questionReviewedContent 正式题目的校验依据
+func questionReviewedContent(q models.Question) reviewedContent {
+	return reviewedContent{q.QuestionType, q.Title, q.CodeSnippet, q.Options, q.Answer}
+}
+
+// saveTempQuestionVerification 保存已落库的临时题目的校验结果（如流式生成的题目）
+func saveTempQuestionVerification(ctx context.Context, questions []models.TempQuestion) error {
+	t := dao.TempQuestion
+	for i := range questions {
+		if _, err := dao.Q.TempQuestion.WithContext(ctx).
+			Where(t.ID.Eq(questions[i].ID)).
+			Select(t.VerificationStatus, t.ReviewerModel, t.ReviewerAnswer, t.ReviewerReasoning).
+			Updates(&questions[i]); err != nil {
+			return fmt.Errorf("保存校验结果失败：%w", err)
+		}
+	}
+	return nil
+}
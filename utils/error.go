@@ -4,10 +4,36 @@ import "errors"
 
 // 自定义错误变量
 var (
-	ErrQuestionNotFound  = errors.New("题目不存在")
-	ErrNoPermission      = errors.New("没有权限")
-	ErrPaperNotFound     = errors.New("试卷不存在或已被删除")
-	ErrDuplicateQuestion = errors.New("题目已存在于试卷中")
-	ErrInvalidOrder      = errors.New("题目顺序重复或无效")
-	ErrScoreExceedTotal  = errors.New("题目总分超过试卷上限")
+	ErrQuestionNotFound       = errors.New("题目不存在")
+	ErrNoPermission           = errors.New("没有权限")
+	ErrPaperNotFound          = errors.New("试卷不存在或已被删除")
+	ErrDuplicateQuestion      = errors.New("题目已存在于试卷中")
+	ErrInvalidOrder           = errors.New("题目顺序重复或无效")
+	ErrScoreExceedTotal       = errors.New("题目总分超过试卷上限")
+	ErrJobNotFound            = errors.New("生成任务不存在")
+	ErrJobQueueFull           = errors.New("生成任务队列已满，请稍后重试")
+	ErrTemplateNotFound       = errors.New("提示语模板不存在")
+	ErrNoPromptTemplate       = errors.New("没有适用的提示语模板")
+	ErrQuotaExceeded          = errors.New("生成配额已用完")
+	ErrUserNotFound           = errors.New("用户不存在")
+	ErrEmptyQuota             = errors.New("至少需要设置一项配额（恢复默认配额请使用删除接口）")
+	ErrTempQuestionNotFound   = errors.New("临时题目不存在或已确认入库")
+	ErrNoPreviousRevision     = errors.New("没有可撤销的历史版本")
+	ErrRevisionConflict       = errors.New("题目已被修改，请刷新后重试")
+	ErrDuplicateRegenerated   = errors.New("重新生成的题目与预览中的题目重复，请调整修改要求后重试")
+	ErrPreviewNotFound        = errors.New("预览不存在或题目已全部确认入库")
+	ErrTempQuestionsMissing   = errors.New("部分临时题目不存在、已丢弃或不属于当前用户")
+	ErrDuplicateSelection     = errors.New("选中的临时题目重复")
+	ErrInvalidEdit            = errors.New("编辑内容不合法")
+	ErrPreviewExpired         = errors.New("预览已过期，请重新生成题目")
+	ErrConfirmConflict        = errors.New("题目正在被其他请求确认，请稍后重试")
+	ErrIdempotencyKeyInUse    = errors.New("相同 Idempotency-Key 的请求正在处理中，请稍后重试")
+	ErrIdempotencyKeyMismatch = errors.New("Idempotency-Key 已用于参数不同的请求")
+	ErrSourceNotFound         = errors.New("出题资料不存在或已被删除")
+	ErrSourceTooLarge         = errors.New("出题资料超过大小上限")
+	ErrSourceNotText          = errors.New("出题资料必须是 UTF-8 编码的文本")
+	ErrSourceEmpty            = errors.New("出题资料内容不能为空")
+	ErrRepositoryDisabled     = errors.New("未配置允许的仓库目录（SOURCE_REPO_ROOTS），不能按本地仓库出题")
+	ErrRepositoryNotAllowed   = errors.New("仓库路径不存在或不在允许的目录中")
+	ErrRepositoryTooLarge     = errors.New("仓库文件过多")
 )